@@ -0,0 +1,84 @@
+// Command ragsync menjaga sebuah direktori (misalnya ekspor wiki) tetap sinkron dengan indeks
+// dokumen: berkas baru di-ingest, berkas yang berubah di-embed ulang, dan dokumen untuk berkas
+// yang dihapus ikut dihapus. Konfigurasi database dan embedding sama dengan server.
+//
+// Penggunaan:
+//
+//	ragsync -dir ./wiki-export [-interval 30s] [-once] [-metadata '{"source":"wiki"}']
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"rag-chat-bot/internal/config"
+	"rag-chat-bot/internal/database"
+	"rag-chat-bot/internal/dirsync"
+	"rag-chat-bot/internal/rag"
+	"syscall"
+	"time"
+)
+
+func main() {
+	dir := flag.String("dir", os.Getenv("SYNC_DIR"), "directory to keep in sync (default $SYNC_DIR)")
+	interval := flag.Duration("interval", 30*time.Second, "time between directory scans")
+	once := flag.Bool("once", false, "sync once and exit")
+	metadataJSON := flag.String("metadata", "", "JSON object added to the metadata of every document")
+	flag.Parse()
+
+	if *dir == "" {
+		log.Fatal("Missing directory: use -dir or SYNC_DIR")
+	}
+	var metadata map[string]interface{}
+	if *metadataJSON != "" {
+		if err := json.Unmarshal([]byte(*metadataJSON), &metadata); err != nil {
+			log.Fatalf("Invalid -metadata: %v", err)
+		}
+	}
+
+	// Load konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+
+	// Inisialisasi koneksi database
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	defer db.Close()
+
+	// Inisialisasi tokenizer, penyedia embedding, chunker dan processor seperti di server
+	indexing, err := rag.NewIndexing(context.Background(), cfg, db)
+	if err != nil {
+		log.Fatalf("Error initializing indexing: %v", err)
+	}
+
+	watcher, err := dirsync.New(indexing.Processor, *dir, dirsync.Options{
+		Interval: *interval,
+		Metadata: metadata,
+	})
+	if err != nil {
+		log.Fatalf("Error initializing directory sync: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		stats, err := watcher.Sync(ctx)
+		if err != nil {
+			log.Fatalf("Error syncing %s: %v", *dir, err)
+		}
+		log.Printf("Synced %s: %s", *dir, stats)
+		return
+	}
+
+	log.Printf("Watching %s every %s", *dir, *interval)
+	watcher.Run(ctx)
+	log.Println("Directory sync stopped")
+}
@@ -9,8 +9,12 @@ import (
 	"os/signal"
 	"rag-chat-bot/internal/api"
 	"rag-chat-bot/internal/config"
+	"rag-chat-bot/internal/crawler"
 	"rag-chat-bot/internal/database"
 	"rag-chat-bot/internal/embedding"
+	"rag-chat-bot/internal/jobs"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/prompt"
 	"rag-chat-bot/internal/rag"
 	"rag-chat-bot/internal/service"
 	"syscall"
@@ -31,18 +35,76 @@ func main() {
 	}
 	defer db.Close()
 
-	// Inisialisasi OpenAI API client
-	openaiClient := embedding.NewOpenAIEmbedding(cfg)
+	// Inisialisasi tokenizer, penyedia embedding, chunker dan processor sesuai konfigurasi
+	indexing, err := rag.NewIndexing(context.Background(), cfg, db)
+	if err != nil {
+		log.Fatalf("Error initializing indexing: %v", err)
+	}
+
+	// Inisialisasi model chat (LLM) sesuai konfigurasi
+	chatModel, err := embedding.NewChatModel(cfg)
+	if err != nil {
+		log.Fatalf("Error initializing chat provider: %v", err)
+	}
+	if chatModel.MaxOutputTokens() >= chatModel.ContextWindow() {
+		log.Fatalf("output reserve (%d tokens) must be smaller than the context window of %s (%d)", chatModel.MaxOutputTokens(), chatModel.ModelName(), chatModel.ContextWindow())
+	}
+	log.Printf("Using chat model %s (context window %d tokens, %d reserved for output)", chatModel.ModelName(), chatModel.ContextWindow(), chatModel.MaxOutputTokens())
+
+	// Muat dan validasi template prompt
+	prompts, err := prompt.Load(cfg.PromptDir, cfg.PromptLanguage)
+	if err != nil {
+		log.Fatalf("Error loading prompt templates: %v", err)
+	}
 
 	// Inisialisasi komponen RAG
-	ragProcessor := rag.NewProcessor(db, openaiClient)
-	ragRetriever := rag.NewRetriever(db, openaiClient, 5) // Ambil 5 dokumen teratas
+	ragProcessor := indexing.Processor
+	ragRetriever := rag.NewRetriever(db, indexing.Embedder, chatModel, rag.RetrieverOptions{
+		MaxResults:       5, // Ambil 5 dokumen teratas
+		MaxContextTokens: cfg.RetrievalMaxContextTokens,
+		Tokens:           indexing.Tokens,
+		Mode:             cfg.RetrievalMode,
+		VectorWeight:     cfg.HybridVectorWeight,
+		KeywordWeight:    cfg.HybridKeywordWeight,
+		RRFK:             cfg.HybridRRFK,
+		MinVectorScore:   cfg.RetrievalMinScore,
+		NoAnswerMode:     cfg.NoAnswerMode,
+		NoAnswerMessage:  cfg.NoAnswerMessage,
+		Prompts:          prompts,
+		HistoryStrategy:  cfg.ChatHistoryStrategy,
+	})
+
+	// Embed dokumen lama yang tersimpan tanpa potongan agar terlihat oleh pencarian
+	go func() {
+		reindexed, err := ragProcessor.ReindexOrphanDocuments(context.Background())
+		if err != nil {
+			log.Printf("Error reindexing documents without chunks: %v", err)
+		} else if reindexed > 0 {
+			log.Printf("Reindexed %d documents without chunks", reindexed)
+		}
+	}()
+
+	// Inisialisasi worker untuk antrean job ingestion
+	jobPool := jobs.NewPool(db, jobs.Options{
+		Workers:        cfg.IngestWorkers,
+		MaxAttempts:    cfg.IngestMaxAttempts,
+		RetryBaseDelay: cfg.IngestRetryBaseDelay,
+	})
+	jobPool.Register(model.JobKindIngestDocument, jobs.IngestDocumentHandler(ragProcessor))
+	jobPool.Register(model.JobKindCrawlSite, jobs.CrawlSiteHandler(ragProcessor, crawler.Options{
+		UserAgent:   cfg.CrawlerUserAgent,
+		MaxDepth:    cfg.CrawlerMaxDepth,
+		MaxPages:    cfg.CrawlerMaxPages,
+		Concurrency: cfg.CrawlerConcurrency,
+		Delay:       cfg.CrawlerDelay,
+	}))
+	jobPool.Start()
 
 	// Inisialisasi service
 	chatService := service.NewChatService(db, ragRetriever)
 
 	// Inisialisasi handler dan router
-	handler := api.NewHandler(chatService, ragProcessor)
+	handler := api.NewHandler(chatService, ragProcessor, jobPool)
 	router := handler.SetupRouter()
 
 	// Konfigurasi server
@@ -80,5 +142,10 @@ func main() {
 
 	// Tunggu hingga server benar-benar berhenti
 	<-done
+
+	// Selesaikan job yang sedang berjalan; job yang belum selesai dikembalikan ke antrean
+	if err := jobPool.Shutdown(ctx); err != nil {
+		log.Printf("Job workers did not finish in time: %v", err)
+	}
 	log.Println("Server stopped")
 }
@@ -7,19 +7,36 @@ CREATE TABLE documents (
     title TEXT NOT NULL,
     content TEXT NOT NULL,
     metadata JSONB DEFAULT '{}'::jsonb,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+    content_hash TEXT, -- SHA-256 konten yang spasinya dinormalisasi, untuk mendeteksi duplikat
+    version INTEGER NOT NULL DEFAULT 1,
+    previous_version_id INTEGER REFERENCES documents(id) ON DELETE SET NULL,
+    superseded_at TIMESTAMP WITH TIME ZONE, -- Diisi saat dokumen digantikan versi baru
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
 
--- Tabel untuk menyimpan embedding vektor dari dokumen
-CREATE TABLE document_embeddings (
+-- Tabel untuk menyimpan potongan dokumen beserta embedding vektornya
+CREATE TABLE document_chunks (
     id SERIAL PRIMARY KEY,
-    document_id INTEGER REFERENCES documents(id) ON DELETE CASCADE,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    chunk_index INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    start_offset INTEGER NOT NULL, -- Posisi byte awal potongan di konten dokumen
+    end_offset INTEGER NOT NULL,   -- Posisi byte akhir potongan di konten dokumen
+    metadata JSONB DEFAULT '{}'::jsonb,
+    content_hash TEXT,
     embedding vector(1536), -- Menggunakan dimensi 1536 untuk OpenAI embeddings
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+    -- Kolom tsvector untuk pencarian kata kunci ('simple' tanpa stemming)
+    content_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (document_id, chunk_index)
 );
 
 -- Indeks untuk pencarian vektor dengan metode HNSW (efisien untuk pencarian knn)
-CREATE INDEX ON document_embeddings USING hnsw (embedding vector_cosine_ops);
+CREATE INDEX ON document_chunks USING hnsw (embedding vector_cosine_ops);
+
+-- Indeks GIN untuk full-text search
+CREATE INDEX idx_document_chunks_content_tsv ON document_chunks USING GIN (content_tsv);
 
 -- Tabel untuk menyimpan percakapan
 CREATE TABLE conversations (
@@ -37,7 +54,33 @@ CREATE TABLE messages (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
 
+-- Antrean job latar belakang untuk ingestion dokumen
+CREATE TABLE ingestion_jobs (
+    id SERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    stage TEXT NOT NULL DEFAULT '',
+    progress_done INTEGER NOT NULL DEFAULT 0,
+    progress_total INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    run_after TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
 -- Indeks untuk membantu kueri
-CREATE INDEX idx_document_embeddings_document_id ON document_embeddings(document_id);
+CREATE INDEX idx_documents_metadata ON documents USING GIN (metadata);
+CREATE INDEX idx_documents_created_at ON documents(created_at);
+CREATE INDEX idx_document_chunks_document_id ON document_chunks(document_id);
+CREATE INDEX idx_document_chunks_content_hash ON document_chunks(content_hash);
+-- Hanya boleh ada satu versi aktif untuk setiap konten
+CREATE UNIQUE INDEX idx_documents_content_hash ON documents(content_hash) WHERE superseded_at IS NULL;
 CREATE INDEX idx_messages_conversation_id ON messages(conversation_id);
-CREATE INDEX idx_conversations_session_id ON conversations(session_id);
\ No newline at end of file
+CREATE INDEX idx_conversations_session_id ON conversations(session_id);
+CREATE INDEX idx_ingestion_jobs_pending ON ingestion_jobs(run_after) WHERE status = 'pending';
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"rag-chat-bot/internal/model"
+)
+
+// HandleCrawl menangani permintaan crawling situs dokumentasi. Crawling selalu berjalan
+// sebagai job latar belakang; respons 202 Accepted berisi ID job yang hasilnya merangkum
+// jumlah halaman yang dibuat, diperbarui, tidak berubah, dilewati dan gagal.
+func (h *Handler) HandleCrawl(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req model.CrawlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validasi permintaan
+	if len(req.URLs) == 0 && len(req.Sitemaps) == 0 {
+		http.Error(w, "At least one of urls or sitemaps is required", http.StatusBadRequest)
+		return
+	}
+	for _, raw := range append(append([]string{}, req.URLs...), req.Sitemaps...) {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			http.Error(w, "Invalid URL: "+raw, http.StatusBadRequest)
+			return
+		}
+	}
+	if (req.MaxDepth != nil && *req.MaxDepth < 0) || req.MaxPages < 0 {
+		http.Error(w, "max_depth and max_pages must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	jobID, err := h.jobs.Enqueue(r.Context(), model.JobKindCrawlSite, req)
+	if err != nil {
+		log.Printf("Error enqueuing crawl: %v", err)
+		http.Error(w, "Error starting crawl", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", jobStatusURL(jobID))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(model.JobAcceptedResponse{
+		Success:   true,
+		JobID:     jobID,
+		Status:    model.JobStatusPending,
+		StatusURL: jobStatusURL(jobID),
+	})
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rag-chat-bot/internal/database"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/rag"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Batas paginasi daftar dokumen
+const (
+	defaultDocumentLimit = 20
+	maxDocumentLimit     = 100
+)
+
+// HandleDocuments menangani koleksi dokumen: GET untuk daftar dokumen, POST untuk menambah dokumen
+func (h *Handler) HandleDocuments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleListDocuments(w, r)
+	case http.MethodPost:
+		h.HandleAddDocument(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDocument menangani satu dokumen berdasarkan ID: GET, PUT, PATCH dan DELETE
+func (h *Handler) HandleDocument(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid document ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetDocument(w, r, id)
+	case http.MethodPut, http.MethodPatch:
+		h.handleUpdateDocument(w, r, id)
+	case http.MethodDelete:
+		h.handleDeleteDocument(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleListDocuments menangani daftar dokumen dengan paginasi (limit, offset) dan filter
+// judul (title), metadata (filter berupa JSON) serta tanggal pembuatan (created_after, created_before)
+func (h *Handler) HandleListDocuments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := database.DocumentListOptions{
+		Title: query.Get("title"),
+		Limit: defaultDocumentLimit,
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		opts.Limit = min(limit, maxDocumentLimit)
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+		opts.Offset = offset
+	}
+
+	var err error
+	if opts.CreatedAfter, err = parseTimeParam(query.Get("created_after")); err != nil {
+		http.Error(w, "Invalid created_after", http.StatusBadRequest)
+		return
+	}
+	if opts.CreatedBefore, err = parseTimeParam(query.Get("created_before")); err != nil {
+		http.Error(w, "Invalid created_before", http.StatusBadRequest)
+		return
+	}
+
+	if v := query.Get("filter"); v != "" {
+		if err := json.Unmarshal([]byte(v), &opts.Filter); err != nil {
+			http.Error(w, "Invalid filter", http.StatusBadRequest)
+			return
+		}
+		if err := opts.Filter.Validate(); err != nil {
+			http.Error(w, "Invalid filter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	documents, total, err := h.processor.ListDocuments(r.Context(), opts)
+	if err != nil {
+		log.Printf("Error listing documents: %v", err)
+		http.Error(w, "Error listing documents", http.StatusInternalServerError)
+		return
+	}
+	if documents == nil {
+		documents = []*model.DocumentSummary{}
+	}
+
+	// Kirim respons
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.ListDocumentsResponse{
+		Success:   true,
+		Documents: documents,
+		Total:     total,
+		Limit:     opts.Limit,
+		Offset:    opts.Offset,
+	})
+}
+
+// handleGetDocument mengirim dokumen lengkap beserta kontennya
+func (h *Handler) handleGetDocument(w http.ResponseWriter, r *http.Request, id int) {
+	doc, err := h.processor.GetDocument(r.Context(), id)
+	if err != nil {
+		writeDocumentError(w, err, "Error getting document")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"document": doc,
+	})
+}
+
+// handleUpdateDocument memperbarui dokumen. PUT mengganti judul, konten dan metadata sekaligus,
+// sedangkan PATCH hanya mengubah field yang dikirim.
+func (h *Handler) handleUpdateDocument(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.UpdateDocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validasi permintaan
+	if r.Method == http.MethodPut {
+		if req.Title == nil || req.Content == nil {
+			http.Error(w, "Title and content are required", http.StatusBadRequest)
+			return
+		}
+		if req.Metadata == nil {
+			req.Metadata = map[string]interface{}{}
+		}
+	}
+	if (req.Title != nil && strings.TrimSpace(*req.Title) == "") || (req.Content != nil && strings.TrimSpace(*req.Content) == "") {
+		http.Error(w, "Title and content cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	doc, reembedded, err := h.processor.UpdateDocument(r.Context(), id, &req)
+	if err != nil {
+		writeDocumentError(w, err, "Error updating document")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.UpdateDocumentResponse{
+		Success:    true,
+		Document:   doc,
+		Reembedded: reembedded,
+	})
+}
+
+// handleDeleteDocument menghapus dokumen beserta embedding-nya
+func (h *Handler) handleDeleteDocument(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.processor.DeleteDocument(r.Context(), id); err != nil {
+		writeDocumentError(w, err, "Error deleting document")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"doc_id":  id,
+	})
+}
+
+// writeDocumentError menulis 404 untuk dokumen yang tidak ada, atau error penyedia model lainnya
+func writeDocumentError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, database.ErrDocumentNotFound) {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, database.ErrDuplicateDocument) {
+		http.Error(w, "Another document already has the same content", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, rag.ErrEmptyDocument) {
+		http.Error(w, "Document has no content to index", http.StatusUnprocessableEntity)
+		return
+	}
+	log.Printf("%s: %v", message, err)
+	writeProviderError(w, err, message)
+}
+
+// parseTimeParam membaca parameter waktu dalam format RFC 3339 atau tanggal (YYYY-MM-DD)
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
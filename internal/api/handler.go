@@ -2,24 +2,32 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"rag-chat-bot/internal/embedding"
+	"rag-chat-bot/internal/jobs"
 	"rag-chat-bot/internal/model"
 	"rag-chat-bot/internal/rag"
 	"rag-chat-bot/internal/service"
+	"strconv"
+	"strings"
 )
 
 // Handler mengelola permintaan API
 type Handler struct {
 	chatService *service.ChatService
 	processor   *rag.Processor
+	jobs        *jobs.Pool
 }
 
 // NewHandler membuat instance Handler baru
-func NewHandler(chatService *service.ChatService, processor *rag.Processor) *Handler {
+func NewHandler(chatService *service.ChatService, processor *rag.Processor, jobPool *jobs.Pool) *Handler {
 	return &Handler{
 		chatService: chatService,
 		processor:   processor,
+		jobs:        jobPool,
 	}
 }
 
@@ -42,11 +50,26 @@ func (h *Handler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := req.Filter.Validate(); err != nil {
+		http.Error(w, "Invalid filter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.chatService.SelectPrompt(&req, r.Header.Get("Accept-Language")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if req.SessionID == "" {
 		// Generate session ID sederhana jika tidak disediakan
 		req.SessionID = generateSessionID()
 	}
 
+	if req.Stream {
+		h.streamChat(w, r, &req)
+		return
+	}
+
 	// Proses pesan
 	resp, err := h.chatService.ProcessUserMessage(r.Context(), &req)
 	if err != nil {
@@ -60,7 +83,22 @@ func (h *Handler) HandleChat(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
-// HandleAddDocument menangani penambahan dokumen baru
+// streamChat mengirim respons chat secara bertahap melalui Server-Sent Events
+func (h *Handler) streamChat(w http.ResponseWriter, r *http.Request, req *model.ChatRequest) {
+	sse, ok := newSSEWriter(w)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.chatService.StreamUserMessage(r.Context(), req, sse.Send); err != nil {
+		log.Printf("Error streaming message: %v", err)
+		sse.Send(model.StreamEventError, map[string]string{"error": "Error processing message"})
+	}
+}
+
+// HandleAddDocument menangani penambahan dokumen baru. Dokumen diproses di latar belakang
+// dan respons 202 Accepted berisi ID job; dengan ?wait=true dokumen diproses langsung.
 func (h *Handler) HandleAddDocument(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -74,10 +112,34 @@ func (h *Handler) HandleAddDocument(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validasi permintaan
-	if req.Title == "" || req.Content == "" {
+	if strings.TrimSpace(req.Title) == "" || strings.TrimSpace(req.Content) == "" {
 		http.Error(w, "Title and content are required", http.StatusBadRequest)
 		return
 	}
+	if !model.ValidDuplicatePolicy(req.OnDuplicate) {
+		http.Error(w, "on_duplicate must be skip, replace or new-version", http.StatusBadRequest)
+		return
+	}
+
+	if wait, _ := strconv.ParseBool(r.URL.Query().Get("wait")); !wait {
+		jobID, err := h.jobs.Enqueue(r.Context(), model.JobKindIngestDocument, req)
+		if err != nil {
+			log.Printf("Error enqueuing document: %v", err)
+			http.Error(w, "Error processing document", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", jobStatusURL(jobID))
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(model.JobAcceptedResponse{
+			Success:   true,
+			JobID:     jobID,
+			Status:    model.JobStatusPending,
+			StatusURL: jobStatusURL(jobID),
+		})
+		return
+	}
 
 	// Buat dan proses dokumen
 	doc := &model.Document{
@@ -86,17 +148,19 @@ func (h *Handler) HandleAddDocument(w http.ResponseWriter, r *http.Request) {
 		Metadata: req.Metadata,
 	}
 
-	docID, err := h.processor.ProcessDocument(r.Context(), doc)
+	result, err := h.processor.IngestDocument(r.Context(), doc, req.OnDuplicate, nil)
 	if err != nil {
-		log.Printf("Error processing document: %v", err)
-		http.Error(w, "Error processing document", http.StatusInternalServerError)
+		writeDocumentError(w, err, "Error processing document")
 		return
 	}
 
 	// Kirim respons
 	resp := model.CreateDocumentResponse{
-		Success: true,
-		DocID:   docID,
+		Success:     true,
+		DocID:       result.DocID,
+		Action:      result.Action,
+		DuplicateOf: result.DuplicateOf,
+		Version:     result.Version,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -133,6 +197,24 @@ func (h *Handler) HandleGetConversation(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// writeProviderError menulis respons error HTTP yang sesuai dengan jenis error dari penyedia model
+func writeProviderError(w http.ResponseWriter, err error, message string) {
+	var rateLimitErr *embedding.RateLimitError
+	var contextLengthErr *embedding.ContextLengthError
+
+	switch {
+	case errors.As(err, &rateLimitErr):
+		if rateLimitErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))))
+		}
+		http.Error(w, message+": provider rate limit exceeded", http.StatusServiceUnavailable)
+	case errors.As(err, &contextLengthErr):
+		http.Error(w, message+": content exceeds the model context length", http.StatusRequestEntityTooLarge)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
 // generateSessionID menghasilkan ID sesi sederhana
 func generateSessionID() string {
 	// Dalam implementasi sebenarnya, gunakan UUID atau ID yang lebih aman
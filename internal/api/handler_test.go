@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleAddDocumentRejectsBlankContent(t *testing.T) {
+	h := NewHandler(nil, nil, nil)
+
+	for _, body := range []string{
+		`{"title": "Panduan", "content": ""}`,
+		`{"title": "Panduan", "content": " \n\t "}`,
+		`{"title": "  ", "content": "Isi dokumen"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/documents?wait=true", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.HandleAddDocument(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("HandleAddDocument(%s) status = %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rag-chat-bot/internal/database"
+	"strconv"
+)
+
+// HandleGetJob menangani pengambilan status job, termasuk tahap, kemajuan, error dan jumlah percobaan
+func (h *Handler) HandleGetJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, database.ErrJobNotFound) {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error getting job: %v", err)
+		http.Error(w, "Error getting job", http.StatusInternalServerError)
+		return
+	}
+
+	// Kirim respons
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"job":     job,
+	})
+}
+
+// jobStatusURL mengembalikan endpoint status job
+func jobStatusURL(jobID int) string {
+	return "/api/jobs/" + strconv.Itoa(jobID)
+}
@@ -11,8 +11,13 @@ func (h *Handler) SetupRouter() http.Handler {
 
 	// API Endpoints
 	mux.HandleFunc("/api/chat", h.HandleChat)
-	mux.HandleFunc("/api/documents", h.HandleAddDocument)
+	mux.HandleFunc("/api/search", h.HandleSearch)
+	mux.HandleFunc("/api/documents", h.HandleDocuments)
+	mux.HandleFunc("/api/documents/upload", h.HandleUploadDocument)
+	mux.HandleFunc("/api/documents/{id}", h.HandleDocument)
+	mux.HandleFunc("/api/crawl", h.HandleCrawl)
 	mux.HandleFunc("/api/conversations", h.HandleGetConversation)
+	mux.HandleFunc("/api/jobs/{id}", h.HandleGetJob)
 
 	// Middleware untuk logging dan CORS
 	return logMiddleware(corsMiddleware(mux))
@@ -30,7 +35,7 @@ func logMiddleware(next http.Handler) http.Handler {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 		if r.Method == http.MethodOptions {
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/rag"
+)
+
+// maxSearchTopK membatasi jumlah hasil yang dapat diminta dalam satu pencarian
+const maxSearchTopK = 50
+
+// HandleSearch menangani pencarian dokumen tanpa menghasilkan jawaban, untuk kotak pencarian
+// dan debugging retrieval
+func (h *Handler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req model.SearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validasi permintaan
+	if req.Query == "" {
+		http.Error(w, "Query cannot be empty", http.StatusBadRequest)
+		return
+	}
+	if req.TopK < 0 || req.TopK > maxSearchTopK {
+		http.Error(w, "top_k must be between 1 and 50", http.StatusBadRequest)
+		return
+	}
+	if req.MinScore < 0 || req.MinScore > 1 {
+		http.Error(w, "min_score must be between 0 and 1", http.StatusBadRequest)
+		return
+	}
+	if req.Mode != "" && !rag.ValidMode(req.Mode) {
+		http.Error(w, "mode must be vector, keyword or hybrid", http.StatusBadRequest)
+		return
+	}
+	if err := req.Filter.Validate(); err != nil {
+		http.Error(w, "Invalid filter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.chatService.SearchDocuments(r.Context(), &req)
+	if err != nil {
+		log.Printf("Error searching documents: %v", err)
+		writeProviderError(w, err, "Error searching documents")
+		return
+	}
+
+	// Kirim respons
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"rag-chat-bot/internal/extract"
+	"rag-chat-bot/internal/model"
+	"strconv"
+)
+
+// Batas ukuran unggahan file dokumen
+const (
+	maxUploadBytes       = 32 << 20 // Total seluruh permintaan
+	maxUploadMemoryBytes = 8 << 20  // Sisanya disimpan sementara di disk
+)
+
+// extractedFile adalah file unggahan yang teksnya sudah diekstrak
+type extractedFile struct {
+	upload  *model.UploadedDocument
+	request model.CreateDocumentRequest
+}
+
+// HandleUploadDocument menangani unggahan file dokumen (multipart/form-data). Setiap bagian
+// "file" diekstrak menjadi teks (PDF, HTML, DOCX, teks biasa dan Markdown) lalu diproses
+// seperti HandleAddDocument. Field opsional "title" (hanya untuk satu file), "metadata"
+// (objek JSON) dan "on_duplicate" diterapkan ke dokumen yang dibuat.
+func (h *Handler) HandleUploadDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	if err := r.ParseMultipartForm(maxUploadMemoryBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("Upload exceeds %d bytes", maxUploadBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["file"]
+	if len(files) == 0 {
+		http.Error(w, "At least one file is required", http.StatusBadRequest)
+		return
+	}
+
+	title := r.FormValue("title")
+	if title != "" && len(files) > 1 {
+		http.Error(w, "Title can only be set when uploading a single file", http.StatusBadRequest)
+		return
+	}
+
+	var metadata map[string]interface{}
+	if v := r.FormValue("metadata"); v != "" {
+		if err := json.Unmarshal([]byte(v), &metadata); err != nil {
+			http.Error(w, "Invalid metadata", http.StatusBadRequest)
+			return
+		}
+	}
+
+	onDuplicate := r.FormValue("on_duplicate")
+	if !model.ValidDuplicatePolicy(onDuplicate) {
+		http.Error(w, "on_duplicate must be skip, replace or new-version", http.StatusBadRequest)
+		return
+	}
+
+	// Ekstrak semua file terlebih dahulu agar tidak ada dokumen yang diproses jika salah satunya gagal
+	extracted := make([]*extractedFile, 0, len(files))
+	for _, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			http.Error(w, "Error reading uploaded file", http.StatusBadRequest)
+			return
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			http.Error(w, "Error reading uploaded file", http.StatusBadRequest)
+			return
+		}
+
+		result, err := extract.Extract(fh.Filename, fh.Header.Get("Content-Type"), data)
+		if err != nil {
+			if errors.Is(err, extract.ErrUnsupportedType) {
+				http.Error(w, fmt.Sprintf("Unsupported file type: %s", fh.Filename), http.StatusUnsupportedMediaType)
+				return
+			}
+			log.Printf("Error extracting %s: %v", fh.Filename, err)
+			http.Error(w, fmt.Sprintf("Error extracting text from %s: %v", fh.Filename, err), http.StatusUnprocessableEntity)
+			return
+		}
+
+		docMetadata := make(map[string]interface{}, len(metadata)+4)
+		maps.Copy(docMetadata, metadata)
+		docMetadata["filename"] = fh.Filename
+		docMetadata["mime_type"] = result.MIMEType
+		docMetadata["size_bytes"] = fh.Size
+		if result.Format == extract.FormatMarkdown {
+			// HTML dan DOCX diubah menjadi Markdown sehingga dipecah per heading
+			docMetadata["format"] = extract.FormatMarkdown
+		}
+
+		docTitle := title
+		if docTitle == "" {
+			docTitle = result.Title
+		}
+		if docTitle == "" {
+			docTitle = fh.Filename
+		}
+
+		extracted = append(extracted, &extractedFile{
+			upload: &model.UploadedDocument{
+				Filename:  fh.Filename,
+				Title:     docTitle,
+				MIMEType:  result.MIMEType,
+				SizeBytes: fh.Size,
+			},
+			request: model.CreateDocumentRequest{
+				Title:       docTitle,
+				Content:     result.Text,
+				Metadata:    docMetadata,
+				OnDuplicate: onDuplicate,
+			},
+		})
+	}
+
+	resp := model.UploadDocumentsResponse{Success: true}
+	wait, _ := strconv.ParseBool(r.URL.Query().Get("wait"))
+
+	for _, file := range extracted {
+		if wait {
+			result, err := h.processor.IngestDocument(r.Context(), &model.Document{
+				Title:    file.request.Title,
+				Content:  file.request.Content,
+				Metadata: file.request.Metadata,
+			}, onDuplicate, nil)
+			if err != nil {
+				writeDocumentError(w, err, fmt.Sprintf("Error processing document %s", file.upload.Filename))
+				return
+			}
+			file.upload.DocID = result.DocID
+			file.upload.Action = result.Action
+			file.upload.DuplicateOf = result.DuplicateOf
+		} else {
+			jobID, err := h.jobs.Enqueue(r.Context(), model.JobKindIngestDocument, file.request)
+			if err != nil {
+				log.Printf("Error enqueuing document %s: %v", file.upload.Filename, err)
+				http.Error(w, "Error processing document", http.StatusInternalServerError)
+				return
+			}
+			file.upload.JobID = jobID
+			file.upload.StatusURL = jobStatusURL(jobID)
+		}
+		resp.Documents = append(resp.Documents, file.upload)
+	}
+
+	// Kirim respons
+	w.Header().Set("Content-Type", "application/json")
+	if !wait {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
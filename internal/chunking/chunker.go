@@ -0,0 +1,193 @@
+package chunking
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Chunk adalah potongan teks dari sebuah dokumen
+type Chunk struct {
+	Index   int
+	Content string
+	// StartOffset dan EndOffset adalah posisi byte potongan di teks asli
+	StartOffset int
+	EndOffset   int
+	Metadata    map[string]interface{}
+}
+
+// Chunker memecah teks dokumen menjadi beberapa potongan untuk di-embed
+type Chunker interface {
+	Chunk(text string) []Chunk
+}
+
+// LengthFunc menghitung panjang teks dalam satuan ukuran potongan (karakter atau token)
+type LengthFunc func(text string) int
+
+// Options mengatur pembuatan Chunker
+type Options struct {
+	// Strategy adalah salah satu dari "fixed", "recursive" atau "sentence"
+	Strategy string
+	Size     int
+	Overlap  int
+	// Length menghitung panjang teks, default-nya jumlah karakter
+	Length LengthFunc
+}
+
+// New membuat Chunker sesuai strategi yang dipilih
+func New(opts Options) (Chunker, error) {
+	if opts.Size <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", opts.Size)
+	}
+	if opts.Overlap < 0 || opts.Overlap >= opts.Size {
+		return nil, fmt.Errorf("chunk overlap must be between 0 and chunk size, got %d", opts.Overlap)
+	}
+	if opts.Length == nil {
+		opts.Length = defaultLength
+	}
+
+	switch opts.Strategy {
+	case "fixed":
+		return &FixedSizeChunker{opts: opts}, nil
+	case "", "recursive":
+		return &RecursiveChunker{opts: opts, Separators: DefaultSeparators}, nil
+	case "sentence":
+		return &SentenceChunker{opts: opts}, nil
+	default:
+		return nil, fmt.Errorf("unsupported chunk strategy: %s", opts.Strategy)
+	}
+}
+
+// defaultLength menghitung panjang teks dalam karakter
+func defaultLength(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// span adalah rentang byte [start, end) di teks asli
+type span struct {
+	start int
+	end   int
+}
+
+// mergeSpans menggabungkan rentang-rentang berurutan menjadi potongan dengan panjang maksimum
+// opts.Size. Potongan berikutnya diawali dengan rentang terakhir dari potongan sebelumnya
+// sepanjang maksimum opts.Overlap.
+func mergeSpans(text string, spans []span, opts Options) []Chunk {
+	lengths := make([]int, len(spans))
+	for i, s := range spans {
+		lengths[i] = opts.Length(text[s.start:s.end])
+	}
+
+	var chunks []Chunk
+	first, total := 0, 0
+
+	emit := func(from, to int) {
+		if from >= to {
+			return
+		}
+		if chunk, ok := newChunk(text, spans[from].start, spans[to-1].end); ok {
+			chunk.Index = len(chunks)
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	for i := range spans {
+		if total+lengths[i] > opts.Size && i > first {
+			emit(first, i)
+
+			// Mundur untuk mengambil rentang yang menjadi overlap
+			next, overlap := i, 0
+			for next > first+1 && overlap+lengths[next-1] <= opts.Overlap {
+				next--
+				overlap += lengths[next]
+			}
+			// Buang rentang overlap terlama jika rentang saat ini tidak muat bersamanya
+			for next < i && overlap+lengths[i] > opts.Size {
+				overlap -= lengths[next]
+				next++
+			}
+			first, total = next, overlap
+		}
+		total += lengths[i]
+	}
+	emit(first, len(spans))
+
+	return chunks
+}
+
+// newChunk membuat potongan dari rentang teks tanpa spasi di awal dan akhir
+func newChunk(text string, start, end int) (Chunk, bool) {
+	raw := text[start:end]
+	trimmedLeft := strings.TrimLeft(raw, " \t\r\n")
+	start += len(raw) - len(trimmedLeft)
+	content := strings.TrimRight(trimmedLeft, " \t\r\n")
+	if content == "" {
+		return Chunk{}, false
+	}
+
+	return Chunk{
+		Content:     content,
+		StartOffset: start,
+		EndOffset:   start + len(content),
+	}, true
+}
+
+// splitBySize memecah rentang yang terlalu panjang menjadi beberapa rentang sepanjang mungkin
+// dengan panjang maksimum opts.Size menurut opts.Length. Rentang dipotong di batas karakter;
+// satu karakter yang sendirian sudah melebihi opts.Size (misalnya emoji yang menjadi beberapa
+// token) tetap menjadi satu rentang.
+func splitBySize(text string, s span, opts Options) []span {
+	// offsets berisi posisi awal setiap karakter, ditambah akhir rentang
+	offsets := make([]int, 0, s.end-s.start+1)
+	for i := range text[s.start:s.end] {
+		offsets = append(offsets, s.start+i)
+	}
+	offsets = append(offsets, s.end)
+	last := len(offsets) - 1
+
+	var spans []span
+	for first := 0; first < last; {
+		fits := func(end int) bool {
+			return opts.Length(text[offsets[first]:offsets[end]]) <= opts.Size
+		}
+
+		// Perbesar rentang dua kali lipat mulai dari opts.Size karakter sampai tidak muat, lalu
+		// cari batas tepatnya dengan pencarian biner
+		lo, hi := first+1, last
+		for probe := min(first+opts.Size, last); ; probe = min(first+2*(probe-first), last) {
+			if !fits(probe) {
+				hi = probe - 1
+				break
+			}
+			lo = probe
+			if probe == last {
+				break
+			}
+		}
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if fits(mid) {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+
+		spans = append(spans, span{start: offsets[first], end: offsets[lo]})
+		first = lo
+	}
+	return spans
+}
+
+// fitSpans memastikan setiap rentang tidak melebihi ukuran maksimum potongan
+func fitSpans(text string, spans []span, opts Options) []span {
+	var fitted []span
+	for _, s := range spans {
+		if opts.Length(text[s.start:s.end]) > opts.Size {
+			fitted = append(fitted, splitBySize(text, s, opts)...)
+			continue
+		}
+		fitted = append(fitted, s)
+	}
+	return fitted
+}
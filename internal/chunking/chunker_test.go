@@ -0,0 +1,127 @@
+package chunking
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMergeSpansOverlapFitsSize(t *testing.T) {
+	text := "aaaabbbbbcccccccc"
+	spans := []span{{0, 4}, {4, 9}, {9, 17}}
+	opts := Options{Size: 10, Overlap: 5, Length: defaultLength}
+
+	var got []string
+	for _, chunk := range mergeSpans(text, spans, opts) {
+		got = append(got, chunk.Content)
+	}
+	// Overlap "bbbbb" dibuang karena "bbbbbcccccccc" melebihi Size
+	want := []string{"aaaabbbbb", "cccccccc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeSpans() = %q, want %q", got, want)
+	}
+}
+
+func TestChunkSizeLimit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var b strings.Builder
+	for i := 0; i < 2000; i++ {
+		b.WriteString(strings.Repeat("x", 1+rng.Intn(30)))
+		switch rng.Intn(10) {
+		case 0:
+			b.WriteString(". ")
+		case 1:
+			b.WriteString("\n\n")
+		case 2:
+			b.WriteString("\n")
+		default:
+			b.WriteString(" ")
+		}
+	}
+	text := b.String()
+
+	for _, strategy := range []string{"fixed", "recursive", "sentence"} {
+		for _, size := range []int{20, 50, 200} {
+			for _, overlap := range []int{0, size / 4, size / 2, size - 1} {
+				opts := Options{Strategy: strategy, Size: size, Overlap: overlap}
+				chunker, err := New(opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				chunks := chunker.Chunk(text)
+				if len(chunks) == 0 {
+					t.Fatalf("%s size=%d overlap=%d: no chunks", strategy, size, overlap)
+				}
+				for _, chunk := range chunks {
+					if n := defaultLength(chunk.Content); n > size {
+						t.Errorf("%s size=%d overlap=%d: chunk %d has length %d", strategy, size, overlap, chunk.Index, n)
+					}
+					if text[chunk.StartOffset:chunk.EndOffset] != chunk.Content {
+						t.Errorf("%s size=%d overlap=%d: chunk %d offsets do not match its content", strategy, size, overlap, chunk.Index)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestChunkSizeLimitMultiByte(t *testing.T) {
+	// Teks CJK dan emoji tanpa spasi menjadi lebih banyak token daripada karakternya; panjang
+	// dalam byte adalah batas atas jumlah token BPE
+	text := strings.Repeat("お誕生日おめでとう🎉、", 40) + strings.Repeat("数据", 100)
+	byteLength := func(s string) int { return len(s) }
+
+	for _, strategy := range []string{"fixed", "recursive", "sentence"} {
+		for _, size := range []int{2, 10, 64} {
+			chunker, err := New(Options{Strategy: strategy, Size: size, Overlap: size / 4, Length: byteLength})
+			if err != nil {
+				t.Fatal(err)
+			}
+			chunks := chunker.Chunk(text)
+			covered := 0
+			for _, chunk := range chunks {
+				// Satu karakter yang lebih panjang dari size tidak dapat dipecah lagi
+				if n := byteLength(chunk.Content); n > size && utf8.RuneCountInString(chunk.Content) > 1 {
+					t.Errorf("%s size=%d: chunk %d has length %d", strategy, size, chunk.Index, n)
+				}
+				if !utf8.ValidString(chunk.Content) {
+					t.Errorf("%s size=%d: chunk %d splits a character", strategy, size, chunk.Index)
+				}
+				covered = max(covered, chunk.EndOffset)
+			}
+			if covered != len(text) {
+				t.Errorf("%s size=%d: chunks end at %d, text has %d bytes", strategy, size, covered, len(text))
+			}
+		}
+	}
+}
+
+func TestSplitBySize(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		size   int
+		length LengthFunc
+		want   []string
+	}{
+		{"characters", "abcdefgh", 3, defaultLength, []string{"abc", "def", "gh"}},
+		{"multi-byte characters", "日本語テキスト", 3, defaultLength, []string{"日本語", "テキス", "ト"}},
+		{"bytes", "日本語テキスト", 7, func(s string) int { return len(s) }, []string{"日本", "語テ", "キス", "ト"}},
+		{"oversized character", "🎉ab", 2, func(s string) int { return len(s) }, []string{"🎉", "ab"}},
+		{"longer than size in characters", strings.Repeat("a", 10), 4, func(s string) int { return (len(s) + 1) / 2 }, []string{"aaaaaaaa", "aa"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range splitBySize(tt.text, span{start: 0, end: len(tt.text)}, Options{Size: tt.size, Length: tt.length}) {
+				got = append(got, tt.text[s.start:s.end])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitBySize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package chunking
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// GoChunker memecah file Go pada deklarasi tingkat atas (fungsi, method, tipe, variabel dan
+// konstanta) beserta komentar dokumentasinya menggunakan go/parser. Komentar lepas di antara
+// deklarasi ikut ke deklarasi berikutnya. File yang tidak dapat di-parse diproses dengan
+// heuristik CodeChunker.
+type GoChunker struct {
+	opts     Options
+	fallback *CodeChunker
+}
+
+// NewGoChunker membuat GoChunker baru
+func NewGoChunker(opts Options) *GoChunker {
+	return &GoChunker{opts: opts, fallback: NewCodeChunker(opts)}
+}
+
+// Chunk memecah kode Go menjadi potongan per deklarasi
+func (c *GoChunker) Chunk(text string) []Chunk {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", text, parser.ParseComments)
+	if err != nil {
+		return c.fallback.Chunk(text)
+	}
+
+	pkg := file.Name.Name
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	// Klausa package beserta dokumentasi paket dan komentar di atasnya (misalnya lisensi atau
+	// build constraint) menjadi potongan tersendiri
+	headerStart := offset(file.Package)
+	if len(file.Comments) > 0 {
+		headerStart = min(headerStart, offset(file.Comments[0].Pos()))
+	}
+	decls := []goDecl{{span: span{start: headerStart, end: offset(file.Name.End())}, symbol: pkg, kind: "package"}}
+
+	for _, decl := range file.Decls {
+		d := goDecl{span: span{start: offset(decl.Pos()), end: offset(decl.End())}}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Doc != nil {
+				d.start = offset(decl.Doc.Pos())
+			}
+			d.kind, d.symbol = "func", decl.Name.Name
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				d.kind, d.symbol = "method", receiverName(decl.Recv.List[0].Type)+"."+decl.Name.Name
+			}
+
+		case *ast.GenDecl:
+			if decl.Doc != nil {
+				d.start = offset(decl.Doc.Pos())
+			}
+			d.kind, d.symbol = decl.Tok.String(), genDeclSymbols(decl)
+
+		default:
+			continue
+		}
+		decls = append(decls, d)
+	}
+
+	attachComments(fset.File(file.Package), file.Comments, decls)
+
+	var chunks []Chunk
+	for _, d := range decls {
+		for _, chunk := range c.splitDecl(text, d.span) {
+			chunk.Index = len(chunks)
+			chunk.Metadata = map[string]interface{}{
+				"package":    pkg,
+				"kind":       d.kind,
+				"start_line": lineAt(text, chunk.StartOffset),
+				"end_line":   lineAt(text, chunk.EndOffset),
+			}
+			if d.symbol != "" {
+				chunk.Metadata["symbol"] = d.symbol
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	return chunks
+}
+
+// goDecl adalah rentang satu deklarasi tingkat atas beserta simbol dan jenisnya
+type goDecl struct {
+	span
+	symbol string
+	kind   string
+}
+
+// attachComments memperluas rentang deklarasi agar komentar di luar deklarasi tidak hilang.
+// Komentar di antara dua deklarasi ikut ke deklarasi berikutnya, kecuali komentar yang berada di
+// baris akhir deklarasi sebelumnya. Komentar setelah deklarasi terakhir ikut ke deklarasi terakhir.
+func attachComments(file *token.File, comments []*ast.CommentGroup, decls []goDecl) {
+	next := 0
+	for i := range decls {
+		declStart := decls[i].start
+		for ; next < len(comments) && file.Offset(comments[next].Pos()) < declStart; next++ {
+			start, end := file.Offset(comments[next].Pos()), file.Offset(comments[next].End())
+			if i == 0 || start < decls[i-1].end {
+				continue // Bagian dari deklarasi sebelumnya
+			}
+			prev := &decls[i-1]
+			if file.Line(comments[next].Pos()) == file.Line(file.Pos(prev.end)) {
+				prev.end = end
+				continue
+			}
+			decls[i].start = min(decls[i].start, start)
+		}
+	}
+
+	last := &decls[len(decls)-1]
+	for _, group := range comments[next:] {
+		last.end = max(last.end, file.Offset(group.End()))
+	}
+}
+
+// splitDecl memecah deklarasi yang melebihi ukuran potongan pada batas baris
+func (c *GoChunker) splitDecl(text string, s span) []Chunk {
+	if c.opts.Length(text[s.start:s.end]) <= c.opts.Size {
+		if chunk, ok := newChunk(text, s.start, s.end); ok {
+			return []Chunk{chunk}
+		}
+		return nil
+	}
+	return mergeSpans(text, fitSpans(text, splitAfter(text, s, "\n"), c.opts), c.opts)
+}
+
+// receiverName mengembalikan nama tipe receiver method, misalnya "Processor" untuk *Processor
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return ""
+	}
+}
+
+// genDeclSymbols mengembalikan nama-nama yang dideklarasikan oleh import, type, var atau const
+func genDeclSymbols(d *ast.GenDecl) string {
+	var names []string
+	for _, spec := range d.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			names = append(names, s.Name.Name)
+		case *ast.ValueSpec:
+			for _, name := range s.Names {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// CodeChunker memecah kode bahasa apa pun dengan heuristik indentasi dan kurung kurawal:
+// blok baru dimulai pada baris tanpa indentasi yang bukan penutup blok, dan komentar di atasnya
+// ikut ke blok tersebut. Blok-blok berurutan kemudian digabung hingga ukuran potongan.
+type CodeChunker struct {
+	opts Options
+}
+
+// NewCodeChunker membuat CodeChunker baru
+func NewCodeChunker(opts Options) *CodeChunker {
+	return &CodeChunker{opts: opts}
+}
+
+// symbolPattern mengenali deklarasi umum dan menangkap nama simbolnya
+var symbolPattern = regexp.MustCompile(`\b(?:func|def|class|function|fn|interface|struct|enum|trait|impl|module|type|object|record)\s+([A-Za-z_$][\w$.]*)`)
+
+// Chunk memecah kode menjadi potongan berdasarkan blok tingkat atas
+func (c *CodeChunker) Chunk(text string) []Chunk {
+	// Blok yang terlalu panjang dipecah pada batas baris
+	var spans []span
+	for _, b := range topLevelBlocks(text) {
+		if c.opts.Length(text[b.start:b.end]) > c.opts.Size {
+			spans = append(spans, fitSpans(text, splitAfter(text, b, "\n"), c.opts)...)
+			continue
+		}
+		spans = append(spans, b)
+	}
+
+	chunks := mergeSpans(text, spans, c.opts)
+	for i := range chunks {
+		chunk := &chunks[i]
+		chunk.Metadata = map[string]interface{}{
+			"start_line": lineAt(text, chunk.StartOffset),
+			"end_line":   lineAt(text, chunk.EndOffset),
+		}
+
+		var symbols []interface{}
+		for _, match := range symbolPattern.FindAllStringSubmatch(chunk.Content, -1) {
+			symbols = append(symbols, match[1])
+		}
+		if len(symbols) > 0 {
+			chunk.Metadata["symbol"] = symbols[0]
+			chunk.Metadata["symbols"] = symbols
+		}
+	}
+
+	return chunks
+}
+
+// topLevelBlocks memecah kode menjadi blok yang masing-masing diawali baris tanpa indentasi
+func topLevelBlocks(text string) []span {
+	var blocks []span
+	blockStart := 0
+	commentStart := -1 // awal komentar yang akan ikut ke blok berikutnya
+
+	for lineStart := 0; lineStart < len(text); {
+		lineEnd := strings.IndexByte(text[lineStart:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(text)
+		} else {
+			lineEnd += lineStart + 1
+		}
+		line := strings.TrimRight(text[lineStart:lineEnd], "\r\n")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			commentStart = -1
+		case line[0] == ' ' || line[0] == '\t' || isBlockCloser(line):
+			commentStart = -1
+		case isLineComment(line):
+			if commentStart < 0 {
+				commentStart = lineStart
+			}
+		default:
+			start := lineStart
+			if commentStart >= 0 {
+				start = commentStart
+			}
+			if start > blockStart {
+				blocks = append(blocks, span{start: blockStart, end: start})
+				blockStart = start
+			}
+			commentStart = -1
+		}
+
+		lineStart = lineEnd
+	}
+
+	if blockStart < len(text) {
+		blocks = append(blocks, span{start: blockStart, end: len(text)})
+	}
+	return blocks
+}
+
+// isBlockCloser menandakan baris penutup blok seperti "}", "});" atau "end"
+func isBlockCloser(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, ")") ||
+		strings.HasPrefix(trimmed, "]") || trimmed == "end"
+}
+
+// isLineComment menandakan baris komentar atau anotasi yang menempel ke deklarasi berikutnya
+func isLineComment(line string) bool {
+	for _, prefix := range []string{"//", "#", "/*", "*", "--", ";", "@"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lineAt mengembalikan nomor baris (mulai dari 1) untuk posisi byte di teks
+func lineAt(text string, offset int) int {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	return strings.Count(text[:offset], "\n") + 1
+}
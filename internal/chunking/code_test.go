@@ -0,0 +1,238 @@
+package chunking
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// codeChunk adalah ringkasan potongan kode yang diharapkan
+type codeChunk struct {
+	symbol    interface{}
+	kind      interface{}
+	startLine int
+	endLine   int
+}
+
+// summarize meringkas potongan menjadi codeChunk dan memeriksa offset-nya
+func summarize(t *testing.T, text string, chunks []Chunk) []codeChunk {
+	t.Helper()
+	got := make([]codeChunk, len(chunks))
+	for i, chunk := range chunks {
+		if chunk.Index != i {
+			t.Errorf("chunk %d has index %d", i, chunk.Index)
+		}
+		if text[chunk.StartOffset:chunk.EndOffset] != chunk.Content {
+			t.Errorf("chunk %d offsets do not match its content", i)
+		}
+		got[i] = codeChunk{
+			symbol:    chunk.Metadata["symbol"],
+			kind:      chunk.Metadata["kind"],
+			startLine: chunk.Metadata["start_line"].(int),
+			endLine:   chunk.Metadata["end_line"].(int),
+		}
+	}
+	return got
+}
+
+// assertCodeChunks membandingkan ringkasan potongan dengan want
+func assertCodeChunks(t *testing.T, got, want []codeChunk) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d chunks %+v, want %d %+v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGoChunker(t *testing.T) {
+	const src = `// Copyright 2024 Contoh.
+
+// Package store menyimpan item.
+package store
+
+import "fmt"
+
+// Bagian konfigurasi
+
+// MaxItems adalah batas item
+const MaxItems = 10 // batas default
+
+type Store struct {
+	items []string
+}
+
+// Add menambahkan item
+func (s *Store) Add(item string) {
+	// periksa batas
+	s.items = append(s.items, item)
+}
+
+func New[T any]() *Store { return &Store{} }
+
+// TODO: hapus setelah migrasi
+`
+	chunks := NewGoChunker(Options{Size: 1000, Length: defaultLength}).Chunk(src)
+
+	assertCodeChunks(t, summarize(t, src, chunks), []codeChunk{
+		{"store", "package", 1, 4},
+		{nil, "import", 6, 6},
+		{"MaxItems", "const", 8, 11},
+		{"Store", "type", 13, 15},
+		{"Store.Add", "method", 17, 21},
+		{"New", "func", 23, 25},
+	})
+
+	// Tidak ada komentar yang hilang di antara deklarasi
+	for _, comment := range []string{"// Copyright 2024 Contoh.", "// Bagian konfigurasi", "// batas default", "// periksa batas", "// TODO: hapus setelah migrasi"} {
+		found := false
+		for _, chunk := range chunks {
+			found = found || strings.Contains(chunk.Content, comment)
+		}
+		if !found {
+			t.Errorf("comment %q is missing from the chunks", comment)
+		}
+	}
+	for _, chunk := range chunks {
+		if chunk.Metadata["package"] != "store" {
+			t.Errorf("chunk %d package = %v, want store", chunk.Index, chunk.Metadata["package"])
+		}
+	}
+}
+
+func TestGoChunkerOversizedFunction(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("package big\n\n// Big melakukan banyak hal\nfunc Big() {\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&b, "\tfmt.Println(%d)\n", i)
+	}
+	b.WriteString("}\n\nfunc Small() {}\n")
+	src := b.String()
+
+	opts := Options{Size: 100, Length: defaultLength}
+	got := summarize(t, src, NewGoChunker(opts).Chunk(src))
+
+	var big []codeChunk
+	for _, chunk := range got {
+		if chunk.symbol == "Big" {
+			big = append(big, chunk)
+		}
+	}
+	if len(big) < 2 {
+		t.Fatalf("oversized function was not split: %+v", got)
+	}
+	if big[0].startLine != 3 || big[len(big)-1].endLine != 35 {
+		t.Errorf("function chunks cover lines %d-%d, want 3-35", big[0].startLine, big[len(big)-1].endLine)
+	}
+	for i := 1; i < len(big); i++ {
+		if big[i].startLine <= big[i-1].startLine {
+			t.Errorf("function chunks are out of order: %+v", big)
+		}
+	}
+	if last := got[len(got)-1]; last.symbol != "Small" || last.startLine != 37 {
+		t.Errorf("last chunk = %+v, want Small at line 37", last)
+	}
+
+	for _, chunk := range NewGoChunker(opts).Chunk(src) {
+		if n := defaultLength(chunk.Content); n > opts.Size {
+			t.Errorf("chunk %d has length %d, limit is %d", chunk.Index, n, opts.Size)
+		}
+		if chunk.EndOffset < len(src) && src[chunk.EndOffset] != '\n' {
+			t.Errorf("chunk %d does not end at a line boundary: %q", chunk.Index, chunk.Content)
+		}
+	}
+}
+
+func TestGoChunkerParseError(t *testing.T) {
+	src := "package broken\n\nfunc Broken( {\n\treturn\n}\n"
+	chunks := NewGoChunker(Options{Size: 1000, Length: defaultLength}).Chunk(src)
+
+	// File yang tidak valid dipecah dengan heuristik CodeChunker
+	assertCodeChunks(t, summarize(t, src, chunks), []codeChunk{{"Broken", nil, 1, 5}})
+}
+
+func TestCodeChunker(t *testing.T) {
+	python := `import os
+
+# Helper untuk membaca berkas
+@cache
+def load(path):
+    return open(path).read()
+
+
+class Reader:
+    def read(self):
+        pass
+`
+	javascript := `// Hitung total belanja
+function total(items) {
+  return items.reduce((sum, item) => sum + item.price, 0);
+}
+
+export class Cart {
+  add(item) {
+    this.items.push(item);
+  }
+}
+`
+
+	tests := []struct {
+		name string
+		text string
+		size int
+		want []codeChunk
+	}{
+		// Ukuran dipilih agar setiap blok muat tetapi dua blok berurutan tidak
+		{"python", python, 90, []codeChunk{
+			{nil, nil, 1, 1},
+			{"load", nil, 3, 6},
+			{"Reader", nil, 9, 11},
+		}},
+		{"javascript", javascript, 120, []codeChunk{
+			{"total", nil, 1, 4},
+			{"Cart", nil, 6, 10},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := NewCodeChunker(Options{Size: tt.size, Length: defaultLength}).Chunk(tt.text)
+			assertCodeChunks(t, summarize(t, tt.text, chunks), tt.want)
+		})
+	}
+
+	chunks := NewCodeChunker(Options{Size: 90, Length: defaultLength}).Chunk(python)
+	if symbols := chunks[2].Metadata["symbols"]; fmt.Sprint(symbols) != "[Reader read]" {
+		t.Errorf("symbols = %v, want [Reader read]", symbols)
+	}
+}
+
+func TestCodeChunkerOversizedBlock(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("function big() {\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&b, "  console.log(%d);\n", i)
+	}
+	b.WriteString("}\n")
+	text := b.String()
+
+	opts := Options{Size: 100, Length: defaultLength}
+	chunks := NewCodeChunker(opts).Chunk(text)
+	if len(chunks) < 2 {
+		t.Fatalf("oversized block was not split: %d chunks", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if n := defaultLength(chunk.Content); n > opts.Size {
+			t.Errorf("chunk %d has length %d, limit is %d", chunk.Index, n, opts.Size)
+		}
+		if chunk.EndOffset < len(text) && text[chunk.EndOffset] != '\n' {
+			t.Errorf("chunk %d does not end at a line boundary: %q", chunk.Index, chunk.Content)
+		}
+	}
+	if chunks[0].Metadata["start_line"] != 1 || chunks[len(chunks)-1].Metadata["end_line"] != 32 {
+		t.Errorf("chunks cover lines %v-%v, want 1-32", chunks[0].Metadata["start_line"], chunks[len(chunks)-1].Metadata["end_line"])
+	}
+}
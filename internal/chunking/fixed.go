@@ -0,0 +1,33 @@
+package chunking
+
+import "unicode"
+
+// FixedSizeChunker memecah teks menjadi potongan berukuran tetap dengan overlap.
+// Batas potongan selalu berada di antara kata sehingga tidak ada kata yang terpotong.
+type FixedSizeChunker struct {
+	opts Options
+}
+
+// Chunk memecah teks menjadi potongan berukuran tetap
+func (c *FixedSizeChunker) Chunk(text string) []Chunk {
+	return mergeSpans(text, fitSpans(text, splitWords(text), c.opts), c.opts)
+}
+
+// splitWords memecah teks menjadi rentang kata beserta spasi yang mengikutinya
+func splitWords(text string) []span {
+	var spans []span
+	start := 0
+	inSpace := false
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if inSpace && !space {
+			spans = append(spans, span{start: start, end: i})
+			start = i
+		}
+		inSpace = space
+	}
+	if start < len(text) {
+		spans = append(spans, span{start: start, end: len(text)})
+	}
+	return spans
+}
@@ -0,0 +1,235 @@
+package chunking
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MarkdownChunker memecah dokumen Markdown berdasarkan hierarki heading. Potongan tidak pernah
+// melewati batas bagian, dan blok kode (fenced code block) maupun tabel hanya dipecah jika
+// melebihi ukuran potongan, dan itu pun hanya di batas baris. Jalur heading disimpan di
+// metadata setiap potongan.
+type MarkdownChunker struct {
+	opts      Options
+	paragraph *RecursiveChunker
+}
+
+// NewMarkdownChunker membuat MarkdownChunker baru
+func NewMarkdownChunker(opts Options) *MarkdownChunker {
+	return &MarkdownChunker{
+		opts:      opts,
+		paragraph: &RecursiveChunker{opts: opts, Separators: DefaultSeparators},
+	}
+}
+
+// mdSection adalah satu bagian dokumen di bawah heading tertentu
+type mdSection struct {
+	headings []string
+	blocks   []mdBlock
+}
+
+// mdBlock adalah blok di dalam bagian; blok atomik (kode dan tabel) sebisa mungkin tidak dipecah
+type mdBlock struct {
+	span
+	atomic bool
+}
+
+// Chunk memecah teks Markdown menjadi potongan per bagian
+func (c *MarkdownChunker) Chunk(text string) []Chunk {
+	var chunks []Chunk
+	for _, section := range parseMarkdown(text) {
+		for _, chunk := range mergeSpans(text, c.sectionSpans(text, section), c.opts) {
+			chunk.Index = len(chunks)
+			chunk.Metadata = sectionMetadata(section.headings)
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// sectionSpans mengubah blok bagian menjadi rentang yang siap digabung. Paragraf yang terlalu
+// panjang dipecah secara rekursif, sedangkan blok atomik dibiarkan utuh kecuali melebihi ukuran
+// potongan. Sebagai upaya terakhir blok atomik seperti itu dipecah per baris, dan baris yang
+// masih terlalu panjang dipecah per ukuran, agar tidak ada potongan yang melebihi batas model
+// embedding.
+func (c *MarkdownChunker) sectionSpans(text string, section mdSection) []span {
+	var spans []span
+	for _, block := range section.blocks {
+		if block.atomic {
+			if c.opts.Length(text[block.start:block.end]) <= c.opts.Size {
+				spans = append(spans, block.span)
+				continue
+			}
+			spans = append(spans, fitSpans(text, splitAfter(text, block.span, "\n"), c.opts)...)
+			continue
+		}
+		spans = append(spans, c.paragraph.split(text, block.span, c.paragraph.Separators)...)
+	}
+	return spans
+}
+
+// sectionMetadata membangun metadata jalur heading untuk potongan
+func sectionMetadata(headings []string) map[string]interface{} {
+	metadata := map[string]interface{}{}
+	if len(headings) == 0 {
+		return metadata
+	}
+
+	path := make([]interface{}, len(headings))
+	for i, h := range headings {
+		path[i] = h
+	}
+	metadata["headings"] = path
+	metadata["section_path"] = strings.Join(headings, " > ")
+	metadata["section_anchor"] = HeadingAnchor(headings[len(headings)-1])
+	return metadata
+}
+
+// parseMarkdown memecah teks Markdown menjadi bagian-bagian berdasarkan heading
+func parseMarkdown(text string) []mdSection {
+	var sections []mdSection
+	var headings []string
+	current := mdSection{}
+
+	var fence string // penanda fence yang sedang terbuka, kosong jika di luar blok kode
+	blockStart := -1 // awal blok yang sedang dibangun, -1 jika tidak ada
+	blockAtomic := false
+
+	closeBlock := func(end int) {
+		if blockStart >= 0 && end > blockStart {
+			current.blocks = append(current.blocks, mdBlock{span: span{start: blockStart, end: end}, atomic: blockAtomic})
+		}
+		blockStart, blockAtomic = -1, false
+	}
+	startBlock := func(start int, atomic bool) {
+		closeBlock(start)
+		blockStart, blockAtomic = start, atomic
+	}
+
+	for lineStart := 0; lineStart < len(text); {
+		lineEnd := strings.IndexByte(text[lineStart:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(text)
+		} else {
+			lineEnd += lineStart + 1
+		}
+		line := strings.TrimRight(text[lineStart:lineEnd], "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+
+		switch {
+		case fence != "":
+			// Di dalam blok kode, hanya cari penutup fence
+			if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+				fence = ""
+				closeBlock(lineEnd)
+			}
+
+		case isFence(trimmed):
+			startBlock(lineStart, true)
+			fence = fenceMarker(trimmed)
+
+		case headingLevel(trimmed) > 0:
+			closeBlock(lineStart)
+			if len(current.blocks) > 0 {
+				sections = append(sections, current)
+			}
+
+			level := headingLevel(trimmed)
+			if len(headings) >= level {
+				headings = headings[:level-1]
+			}
+			for len(headings) < level-1 {
+				headings = append(headings, "")
+			}
+			headings = append(headings, headingText(trimmed, level))
+
+			current = mdSection{headings: compactHeadings(headings)}
+			// Baris heading ikut menjadi blok pertama bagian
+			current.blocks = append(current.blocks, mdBlock{span: span{start: lineStart, end: lineEnd}})
+
+		case strings.HasPrefix(trimmed, "|"):
+			if blockStart < 0 || !blockAtomic {
+				startBlock(lineStart, true)
+			}
+
+		case strings.TrimSpace(line) == "":
+			closeBlock(lineStart)
+
+		default:
+			if blockStart < 0 || blockAtomic {
+				startBlock(lineStart, false)
+			}
+		}
+
+		lineStart = lineEnd
+	}
+
+	closeBlock(len(text))
+	if len(current.blocks) > 0 {
+		sections = append(sections, current)
+	}
+
+	return sections
+}
+
+// isFence menandakan baris pembuka blok kode (``` atau ~~~)
+func isFence(line string) bool {
+	return strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~")
+}
+
+// fenceMarker mengembalikan penanda fence lengkap, misalnya "````"
+func fenceMarker(line string) string {
+	marker := line[:1]
+	n := 0
+	for n < len(line) && line[n] == marker[0] {
+		n++
+	}
+	return line[:n]
+}
+
+// headingLevel mengembalikan level heading ATX (1-6), atau 0 jika bukan heading
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0
+	}
+	if level < len(line) && line[level] != ' ' && line[level] != '\t' {
+		return 0
+	}
+	return level
+}
+
+// headingText mengambil teks heading tanpa penanda # di awal dan akhir
+func headingText(line string, level int) string {
+	text := strings.TrimSpace(line[level:])
+	text = strings.TrimRight(text, "#")
+	return strings.TrimSpace(text)
+}
+
+// compactHeadings membuang level heading yang dilewati (misalnya ## langsung setelah #### )
+func compactHeadings(headings []string) []string {
+	var compact []string
+	for _, h := range headings {
+		if h != "" {
+			compact = append(compact, h)
+		}
+	}
+	return compact
+}
+
+// HeadingAnchor membuat anchor gaya GitHub dari teks heading, misalnya "Install on Linux" -> "install-on-linux"
+func HeadingAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '-':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+package chunking
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownAtomicBlocks(t *testing.T) {
+	var code, table strings.Builder
+	code.WriteString("```go\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&code, "fmt.Println(%d)\n", i)
+	}
+	code.WriteString("```\n")
+	table.WriteString("| Key | Value |\n| --- | --- |\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&table, "| key%d | value%d |\n", i, i)
+	}
+
+	text := "# Guide\n\nShort intro.\n\n```sh\ngo build ./...\n```\n\n" +
+		"## Code\n\n" + code.String() + "\n" +
+		"## Table\n\n" + table.String() + "\n" +
+		"## Long line\n\n```\n" + strings.Repeat("x", 250) + "\n```\n"
+
+	opts := Options{Size: 100, Overlap: 0, Length: defaultLength}
+	chunks := NewMarkdownChunker(opts).Chunk(text)
+
+	smallBlock := false
+	sections := map[string]int{}
+	for _, chunk := range chunks {
+		if n := defaultLength(chunk.Content); n > opts.Size {
+			t.Errorf("chunk %d has length %d, limit is %d", chunk.Index, n, opts.Size)
+		}
+		if text[chunk.StartOffset:chunk.EndOffset] != chunk.Content {
+			t.Errorf("chunk %d offsets do not match its content", chunk.Index)
+		}
+		if strings.Contains(chunk.Content, "```sh\ngo build ./...\n```") {
+			smallBlock = true
+		}
+		section, _ := chunk.Metadata["section_path"].(string)
+		sections[section]++
+
+		// Blok atomik yang terlalu besar hanya dipecah di batas baris
+		if section == "Guide > Code" || section == "Guide > Table" {
+			if chunk.EndOffset < len(text) && text[chunk.EndOffset] != '\n' {
+				t.Errorf("chunk %d in %s does not end at a line boundary: %q", chunk.Index, section, chunk.Content)
+			}
+		}
+	}
+
+	if !smallBlock {
+		t.Errorf("code block that fits was split")
+	}
+	for _, section := range []string{"Guide > Code", "Guide > Table", "Guide > Long line"} {
+		if sections[section] < 2 {
+			t.Errorf("oversized block in %s was not split: %d chunks", section, sections[section])
+		}
+	}
+}
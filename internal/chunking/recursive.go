@@ -0,0 +1,55 @@
+package chunking
+
+import "strings"
+
+// DefaultSeparators adalah urutan pemisah yang dicoba oleh RecursiveChunker,
+// dari batas paragraf hingga batas kata
+var DefaultSeparators = []string{"\n\n", "\n", ". ", "! ", "? ", " "}
+
+// RecursiveChunker memecah teks berdasarkan pemisah secara bertingkat. Teks dipecah dengan
+// pemisah pertama, dan bagian yang masih terlalu panjang dipecah lagi dengan pemisah berikutnya.
+type RecursiveChunker struct {
+	opts       Options
+	Separators []string
+}
+
+// Chunk memecah teks secara rekursif berdasarkan pemisah
+func (c *RecursiveChunker) Chunk(text string) []Chunk {
+	spans := c.split(text, span{start: 0, end: len(text)}, c.Separators)
+	return mergeSpans(text, spans, c.opts)
+}
+
+// split memecah rentang dengan pemisah pertama, lalu memecah ulang bagian yang terlalu panjang
+func (c *RecursiveChunker) split(text string, s span, separators []string) []span {
+	if c.opts.Length(text[s.start:s.end]) <= c.opts.Size {
+		return []span{s}
+	}
+	if len(separators) == 0 {
+		return splitBySize(text, s, c.opts)
+	}
+
+	var spans []span
+	for _, part := range splitAfter(text, s, separators[0]) {
+		spans = append(spans, c.split(text, part, separators[1:])...)
+	}
+	return spans
+}
+
+// splitAfter memecah rentang setelah setiap kemunculan pemisah, pemisah tetap ikut di bagian kiri
+func splitAfter(text string, s span, separator string) []span {
+	var spans []span
+	start := s.start
+	for start < s.end {
+		idx := strings.Index(text[start:s.end], separator)
+		if idx < 0 {
+			break
+		}
+		end := start + idx + len(separator)
+		spans = append(spans, span{start: start, end: end})
+		start = end
+	}
+	if start < s.end {
+		spans = append(spans, span{start: start, end: s.end})
+	}
+	return spans
+}
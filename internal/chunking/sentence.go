@@ -0,0 +1,81 @@
+package chunking
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// SentenceChunker memecah teks pada batas kalimat lalu menggabungkan kalimat-kalimat
+// berurutan menjadi potongan. Overlap dihitung dalam kalimat utuh.
+type SentenceChunker struct {
+	opts Options
+}
+
+// Chunk memecah teks menjadi potongan yang tersusun dari kalimat utuh
+func (c *SentenceChunker) Chunk(text string) []Chunk {
+	return mergeSpans(text, fitSpans(text, splitSentences(text), c.opts), c.opts)
+}
+
+// splitSentences memecah teks menjadi rentang kalimat. Kalimat berakhir pada tanda baca
+// penutup (. ! ? …) yang diikuti spasi dan huruf kapital, angka atau akhir teks, atau pada
+// baris kosong.
+func splitSentences(text string) []span {
+	var spans []span
+	start := 0
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		next := i + size
+
+		switch {
+		case r == '\n' && next < len(text) && text[next] == '\n':
+			end := skipSpaces(text, next)
+			spans = append(spans, span{start: start, end: end})
+			start, next = end, end
+		case isSentenceTerminal(r):
+			// Sertakan tanda baca dan tanda kutip penutup yang berurutan
+			for next < len(text) {
+				r2, size2 := utf8.DecodeRuneInString(text[next:])
+				if !isSentenceTerminal(r2) && r2 != '"' && r2 != '\'' && r2 != ')' && r2 != '”' {
+					break
+				}
+				next += size2
+			}
+			end := skipSpaces(text, next)
+			if end == len(text) || (end > next && startsSentence(text[end:])) {
+				spans = append(spans, span{start: start, end: end})
+				start, next = end, end
+			}
+		}
+
+		i = next
+	}
+
+	if start < len(text) {
+		spans = append(spans, span{start: start, end: len(text)})
+	}
+	return spans
+}
+
+// isSentenceTerminal menandakan tanda baca yang dapat mengakhiri kalimat
+func isSentenceTerminal(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
+
+// startsSentence menandakan teks yang dapat menjadi awal kalimat baru
+func startsSentence(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsUpper(r) || unicode.IsDigit(r) || r == '"' || r == '“' || r == '(' || r == '-' || r == '*'
+}
+
+// skipSpaces mengembalikan posisi karakter non-spasi pertama mulai dari i
+func skipSpaces(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
@@ -0,0 +1,171 @@
+package chunking
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Format dokumen yang menentukan Chunker yang digunakan
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatGo       = "go"
+	FormatCode     = "code"
+)
+
+// codeLanguages memetakan ekstensi file kode sumber ke nama bahasanya
+var codeLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".mjs":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".java":  "java",
+	".kt":    "kotlin",
+	".scala": "scala",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".php":   "php",
+	".swift": "swift",
+	".sh":    "shell",
+	".sql":   "sql",
+	".lua":   "lua",
+}
+
+// languageAliases memetakan nama lain bahasa pemrograman di metadata "language" ke nama di
+// codeLanguages
+var languageAliases = map[string]string{
+	"golang": "go",
+	"py":     "python",
+	"js":     "javascript",
+	"ts":     "typescript",
+	"c++":    "cpp",
+	"c#":     "csharp",
+	"bash":   "shell",
+	"sh":     "shell",
+}
+
+// Set memilih Chunker yang sesuai dengan format dokumen
+type Set struct {
+	Default  Chunker
+	Markdown Chunker
+	Go       Chunker
+	Code     Chunker
+}
+
+// NewSet membuat Set dengan Chunker default sesuai strategi di opts
+func NewSet(opts Options) (*Set, error) {
+	def, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Length == nil {
+		opts.Length = defaultLength
+	}
+
+	// Potongan kode tidak memakai overlap agar setiap deklarasi hanya muncul sekali
+	codeOpts := opts
+	codeOpts.Overlap = 0
+
+	return &Set{
+		Default:  def,
+		Markdown: NewMarkdownChunker(opts),
+		Go:       NewGoChunker(codeOpts),
+		Code:     NewCodeChunker(codeOpts),
+	}, nil
+}
+
+// For mengembalikan Chunker untuk format tertentu
+func (s *Set) For(format string) Chunker {
+	switch format {
+	case FormatMarkdown:
+		return s.Markdown
+	case FormatGo:
+		return s.Go
+	case FormatCode:
+		return s.Code
+	default:
+		return s.Default
+	}
+}
+
+// DetectFormat menentukan format dokumen dari metadata "format", metadata "language" atau
+// ekstensi nama file (metadata "filename", "path" atau judul dokumen)
+func DetectFormat(title string, metadata map[string]interface{}) string {
+	if format, ok := metadata["format"].(string); ok && format != "" {
+		return strings.ToLower(format)
+	}
+
+	switch metadataLanguage(metadata) {
+	case "":
+	case "go":
+		return FormatGo
+	default:
+		return FormatCode
+	}
+
+	for _, name := range documentNames(title, metadata) {
+		ext := strings.ToLower(filepath.Ext(name))
+		switch {
+		case ext == ".md" || ext == ".markdown" || ext == ".mdx":
+			return FormatMarkdown
+		case ext == ".go":
+			return FormatGo
+		case codeLanguages[ext] != "":
+			return FormatCode
+		}
+	}
+
+	return FormatText
+}
+
+// DetectLanguage mengembalikan bahasa pemrograman dokumen berdasarkan metadata "language"
+// atau ekstensi nama file, kosong jika bukan kode sumber
+func DetectLanguage(title string, metadata map[string]interface{}) string {
+	if language := metadataLanguage(metadata); language != "" {
+		return language
+	}
+
+	for _, name := range documentNames(title, metadata) {
+		if language := codeLanguages[strings.ToLower(filepath.Ext(name))]; language != "" {
+			return language
+		}
+	}
+
+	return ""
+}
+
+// metadataLanguage mengembalikan bahasa pemrograman dari metadata "language", kosong jika
+// metadata tidak diisi atau bukan bahasa pemrograman yang dikenali
+func metadataLanguage(metadata map[string]interface{}) string {
+	language, _ := metadata["language"].(string)
+	language = strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	for _, known := range codeLanguages {
+		if known == language {
+			return language
+		}
+	}
+	return ""
+}
+
+// documentNames mengembalikan kandidat nama file dokumen, dari metadata lalu judul
+func documentNames(title string, metadata map[string]interface{}) []string {
+	var names []string
+	for _, key := range []string{"filename", "path"} {
+		if name, ok := metadata[key].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	return append(names, title)
+}
@@ -0,0 +1,37 @@
+package chunking
+
+import "testing"
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		metadata map[string]interface{}
+		format   string
+		language string
+	}{
+		{"plain text", "Catatan rapat", nil, FormatText, ""},
+		{"markdown extension", "README.md", nil, FormatMarkdown, ""},
+		{"go extension", "main.go", nil, FormatGo, "go"},
+		{"code extension", "app.py", nil, FormatCode, "python"},
+		{"filename metadata", "Handler", map[string]interface{}{"filename": "handler.ts"}, FormatCode, "typescript"},
+		{"format metadata wins", "main.go", map[string]interface{}{"format": "Markdown"}, FormatMarkdown, "go"},
+		{"go language metadata", "Handler", map[string]interface{}{"language": "Go"}, FormatGo, "go"},
+		{"language alias", "Handler", map[string]interface{}{"language": "golang"}, FormatGo, "go"},
+		{"code language metadata", "Script", map[string]interface{}{"language": "python"}, FormatCode, "python"},
+		{"language metadata wins over extension", "notes.txt", map[string]interface{}{"language": "rust"}, FormatCode, "rust"},
+		{"natural language metadata", "Panduan", map[string]interface{}{"language": "id"}, FormatText, ""},
+		{"unknown language falls back to extension", "main.go", map[string]interface{}{"language": "en"}, FormatGo, "go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.title, tt.metadata); got != tt.format {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.format)
+			}
+			if got := DetectLanguage(tt.title, tt.metadata); got != tt.language {
+				t.Errorf("DetectLanguage() = %q, want %q", got, tt.language)
+			}
+		})
+	}
+}
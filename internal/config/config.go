@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -21,10 +22,77 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 
+	// Embedding
+	EmbeddingProvider       string
+	EmbeddingDimension      int
+	EmbeddingBatchSize      int
+	EmbeddingBatchMaxTokens int
+	EmbeddingConcurrency    int
+
+	// Tokenizer
+	TokenizerVocabFile string
+
+	// Chunking
+	ChunkStrategy string
+	ChunkSize     int
+	ChunkOverlap  int
+	ChunkSizeUnit string // "tokens" atau "chars"
+
+	// Antrean job ingestion
+	IngestWorkers        int
+	IngestMaxAttempts    int
+	IngestRetryBaseDelay time.Duration
+
+	// Crawler
+	CrawlerUserAgent   string
+	CrawlerConcurrency int
+	CrawlerMaxPages    int
+	CrawlerMaxDepth    int
+	CrawlerDelay       time.Duration
+
+	// Retrieval
+	RetrievalMaxContextTokens int
+	RetrievalMode             string // "vector", "keyword" atau "hybrid"
+	HybridVectorWeight        float64
+	HybridKeywordWeight       float64
+	HybridRRFK                int
+	RetrievalMinScore         float64 // Cosine similarity minimum, 0 menonaktifkan batas
+	NoAnswerMode              string  // "conversation", "refuse" atau "general"
+	NoAnswerMessage           string  // Kosong memakai template no_answer sesuai bahasa
+
+	// Template prompt
+	PromptDir      string // Direktori template yang menimpa template bawaan
+	PromptLanguage string // Bahasa default template prompt
+
+	// Chat
+	ChatProvider        string
+	ChatContextWindow   int    // 0 memakai context window bawaan model
+	ChatMaxOutputTokens int    // Batas panjang jawaban, 0 tidak membatasi
+	ChatHistoryStrategy string // "truncate" atau "summarize"
+
 	// OpenAI
 	OpenAIAPIKey         string
 	OpenAIEmbeddingModel string
 	OpenAIChatModel      string
+	OpenAIBaseURL        string
+	OpenAIOrganization   string
+	OpenAIProject        string
+	OpenAIAPIType        string // "openai" atau "azure"
+
+	// Retry untuk permintaan ke OpenAI API
+	OpenAIRetryMaxAttempts int
+	OpenAIRetryBaseDelay   time.Duration
+	OpenAIRetryMaxDelay    time.Duration
+
+	// Azure OpenAI
+	AzureOpenAIAPIVersion          string
+	AzureOpenAIEmbeddingDeployment string
+	AzureOpenAIChatDeployment      string
+
+	// Ollama
+	OllamaBaseURL        string
+	OllamaEmbeddingModel string
+	OllamaChatModel      string
 }
 
 // LoadConfig memuat konfigurasi dari variabel lingkungan
@@ -53,13 +121,186 @@ func LoadConfig() (*Config, error) {
 	config.DBName = getEnvOrDefault("DB_NAME", "ragchatbot")
 	config.DBSSLMode = getEnvOrDefault("DB_SSL_MODE", "disable")
 
+	// Embedding config
+	config.EmbeddingProvider = getEnvOrDefault("EMBEDDING_PROVIDER", "openai")
+	embeddingDimension, err := strconv.Atoi(getEnvOrDefault("EMBEDDING_DIMENSION", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMBEDDING_DIMENSION: %w", err)
+	}
+	config.EmbeddingDimension = embeddingDimension
+	embeddingBatchSize, err := strconv.Atoi(getEnvOrDefault("EMBEDDING_BATCH_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMBEDDING_BATCH_SIZE: %w", err)
+	}
+	config.EmbeddingBatchSize = embeddingBatchSize
+	embeddingBatchMaxTokens, err := strconv.Atoi(getEnvOrDefault("EMBEDDING_BATCH_MAX_TOKENS", "100000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMBEDDING_BATCH_MAX_TOKENS: %w", err)
+	}
+	config.EmbeddingBatchMaxTokens = embeddingBatchMaxTokens
+	embeddingConcurrency, err := strconv.Atoi(getEnvOrDefault("EMBEDDING_CONCURRENCY", "4"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMBEDDING_CONCURRENCY: %w", err)
+	}
+	config.EmbeddingConcurrency = embeddingConcurrency
+
+	// Tokenizer config
+	config.TokenizerVocabFile = getEnvOrDefault("TOKENIZER_VOCAB_FILE", "assets/cl100k_base.tiktoken")
+
+	// Chunking config
+	config.ChunkStrategy = getEnvOrDefault("CHUNK_STRATEGY", "recursive")
+	chunkSize, err := strconv.Atoi(getEnvOrDefault("CHUNK_SIZE", "400"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHUNK_SIZE: %w", err)
+	}
+	config.ChunkSize = chunkSize
+	chunkOverlap, err := strconv.Atoi(getEnvOrDefault("CHUNK_OVERLAP", "50"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHUNK_OVERLAP: %w", err)
+	}
+	config.ChunkOverlap = chunkOverlap
+	config.ChunkSizeUnit = getEnvOrDefault("CHUNK_SIZE_UNIT", "tokens")
+	if config.ChunkSizeUnit != "tokens" && config.ChunkSizeUnit != "chars" {
+		return nil, fmt.Errorf("invalid CHUNK_SIZE_UNIT: %s", config.ChunkSizeUnit)
+	}
+
+	// Ingestion job queue config
+	ingestWorkers, err := strconv.Atoi(getEnvOrDefault("INGEST_WORKERS", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INGEST_WORKERS: %w", err)
+	}
+	config.IngestWorkers = ingestWorkers
+	ingestMaxAttempts, err := strconv.Atoi(getEnvOrDefault("INGEST_MAX_ATTEMPTS", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INGEST_MAX_ATTEMPTS: %w", err)
+	}
+	config.IngestMaxAttempts = ingestMaxAttempts
+	ingestRetryBaseDelay, err := time.ParseDuration(getEnvOrDefault("INGEST_RETRY_BASE_DELAY", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INGEST_RETRY_BASE_DELAY: %w", err)
+	}
+	config.IngestRetryBaseDelay = ingestRetryBaseDelay
+
+	// Crawler config
+	config.CrawlerUserAgent = getEnvOrDefault("CRAWLER_USER_AGENT", "rag-chat-bot-crawler/1.0")
+	crawlerConcurrency, err := strconv.Atoi(getEnvOrDefault("CRAWLER_CONCURRENCY", "4"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWLER_CONCURRENCY: %w", err)
+	}
+	config.CrawlerConcurrency = crawlerConcurrency
+	crawlerMaxPages, err := strconv.Atoi(getEnvOrDefault("CRAWLER_MAX_PAGES", "500"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWLER_MAX_PAGES: %w", err)
+	}
+	config.CrawlerMaxPages = crawlerMaxPages
+	crawlerMaxDepth, err := strconv.Atoi(getEnvOrDefault("CRAWLER_MAX_DEPTH", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWLER_MAX_DEPTH: %w", err)
+	}
+	config.CrawlerMaxDepth = crawlerMaxDepth
+	crawlerDelay, err := time.ParseDuration(getEnvOrDefault("CRAWLER_DELAY", "500ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWLER_DELAY: %w", err)
+	}
+	config.CrawlerDelay = crawlerDelay
+
+	// Retrieval config
+	retrievalMaxContextTokens, err := strconv.Atoi(getEnvOrDefault("RETRIEVAL_MAX_CONTEXT_TOKENS", "3000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETRIEVAL_MAX_CONTEXT_TOKENS: %w", err)
+	}
+	config.RetrievalMaxContextTokens = retrievalMaxContextTokens
+	config.RetrievalMode = getEnvOrDefault("RETRIEVAL_MODE", "hybrid")
+	if config.RetrievalMode != "vector" && config.RetrievalMode != "keyword" && config.RetrievalMode != "hybrid" {
+		return nil, fmt.Errorf("invalid RETRIEVAL_MODE: %s", config.RetrievalMode)
+	}
+	hybridVectorWeight, err := strconv.ParseFloat(getEnvOrDefault("HYBRID_VECTOR_WEIGHT", "1.0"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HYBRID_VECTOR_WEIGHT: %w", err)
+	}
+	config.HybridVectorWeight = hybridVectorWeight
+	hybridKeywordWeight, err := strconv.ParseFloat(getEnvOrDefault("HYBRID_KEYWORD_WEIGHT", "1.0"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HYBRID_KEYWORD_WEIGHT: %w", err)
+	}
+	config.HybridKeywordWeight = hybridKeywordWeight
+	hybridRRFK, err := strconv.Atoi(getEnvOrDefault("HYBRID_RRF_K", "60"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HYBRID_RRF_K: %w", err)
+	}
+	config.HybridRRFK = hybridRRFK
+	retrievalMinScore, err := strconv.ParseFloat(getEnvOrDefault("RETRIEVAL_MIN_SCORE", "0.25"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETRIEVAL_MIN_SCORE: %w", err)
+	}
+	config.RetrievalMinScore = retrievalMinScore
+	config.NoAnswerMode = getEnvOrDefault("NO_ANSWER_MODE", "refuse")
+	if config.NoAnswerMode != "conversation" && config.NoAnswerMode != "refuse" && config.NoAnswerMode != "general" {
+		return nil, fmt.Errorf("invalid NO_ANSWER_MODE: %s", config.NoAnswerMode)
+	}
+	config.NoAnswerMessage = getEnvOrDefault("NO_ANSWER_MESSAGE", "")
+
+	// Prompt config
+	config.PromptDir = getEnvOrDefault("PROMPT_DIR", "")
+	config.PromptLanguage = getEnvOrDefault("PROMPT_LANGUAGE", "id")
+
+	// Chat config
+	config.ChatProvider = getEnvOrDefault("CHAT_PROVIDER", "openai")
+	chatContextWindow, err := strconv.Atoi(getEnvOrDefault("CHAT_CONTEXT_WINDOW", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHAT_CONTEXT_WINDOW: %w", err)
+	}
+	config.ChatContextWindow = chatContextWindow
+	chatMaxOutputTokens, err := strconv.Atoi(getEnvOrDefault("CHAT_MAX_OUTPUT_TOKENS", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHAT_MAX_OUTPUT_TOKENS: %w", err)
+	}
+	config.ChatMaxOutputTokens = chatMaxOutputTokens
+	config.ChatHistoryStrategy = getEnvOrDefault("CHAT_HISTORY_STRATEGY", "truncate")
+	if config.ChatHistoryStrategy != "truncate" && config.ChatHistoryStrategy != "summarize" {
+		return nil, fmt.Errorf("invalid CHAT_HISTORY_STRATEGY: %s", config.ChatHistoryStrategy)
+	}
+
 	// OpenAI config
 	config.OpenAIAPIKey = getEnvOrDefault("OPENAI_API_KEY", "")
-	if config.OpenAIAPIKey == "" {
+	if config.OpenAIAPIKey == "" && (config.EmbeddingProvider == "openai" || config.ChatProvider == "openai") {
 		return nil, fmt.Errorf("OPENAI_API_KEY is required")
 	}
 	config.OpenAIEmbeddingModel = getEnvOrDefault("OPENAI_EMBEDDING_MODEL", "text-embedding-ada-002")
 	config.OpenAIChatModel = getEnvOrDefault("OPENAI_CHAT_MODEL", "gpt-3.5-turbo")
+	config.OpenAIBaseURL = getEnvOrDefault("OPENAI_BASE_URL", "https://api.openai.com/v1")
+	config.OpenAIOrganization = getEnvOrDefault("OPENAI_ORGANIZATION", "")
+	config.OpenAIProject = getEnvOrDefault("OPENAI_PROJECT", "")
+	config.OpenAIAPIType = getEnvOrDefault("OPENAI_API_TYPE", "openai")
+	if config.OpenAIAPIType != "openai" && config.OpenAIAPIType != "azure" {
+		return nil, fmt.Errorf("invalid OPENAI_API_TYPE: %s", config.OpenAIAPIType)
+	}
+
+	openAIRetryMaxAttempts, err := strconv.Atoi(getEnvOrDefault("OPENAI_RETRY_MAX_ATTEMPTS", "4"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OPENAI_RETRY_MAX_ATTEMPTS: %w", err)
+	}
+	config.OpenAIRetryMaxAttempts = openAIRetryMaxAttempts
+	openAIRetryBaseDelay, err := time.ParseDuration(getEnvOrDefault("OPENAI_RETRY_BASE_DELAY", "500ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OPENAI_RETRY_BASE_DELAY: %w", err)
+	}
+	config.OpenAIRetryBaseDelay = openAIRetryBaseDelay
+	openAIRetryMaxDelay, err := time.ParseDuration(getEnvOrDefault("OPENAI_RETRY_MAX_DELAY", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OPENAI_RETRY_MAX_DELAY: %w", err)
+	}
+	config.OpenAIRetryMaxDelay = openAIRetryMaxDelay
+
+	// Azure OpenAI memakai nama deployment, default-nya sama dengan nama model
+	config.AzureOpenAIAPIVersion = getEnvOrDefault("AZURE_OPENAI_API_VERSION", "2024-02-01")
+	config.AzureOpenAIEmbeddingDeployment = getEnvOrDefault("AZURE_OPENAI_EMBEDDING_DEPLOYMENT", config.OpenAIEmbeddingModel)
+	config.AzureOpenAIChatDeployment = getEnvOrDefault("AZURE_OPENAI_CHAT_DEPLOYMENT", config.OpenAIChatModel)
+
+	// Ollama config
+	config.OllamaBaseURL = getEnvOrDefault("OLLAMA_BASE_URL", "http://localhost:11434")
+	config.OllamaEmbeddingModel = getEnvOrDefault("OLLAMA_EMBEDDING_MODEL", "nomic-embed-text")
+	config.OllamaChatModel = getEnvOrDefault("OLLAMA_CHAT_MODEL", "llama3")
 
 	return config, nil
 }
@@ -0,0 +1,469 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"rag-chat-bot/internal/extract"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodyBytes membatasi ukuran halaman dan sitemap yang diunduh
+const maxBodyBytes = 10 << 20
+
+// skipExtensions adalah ekstensi tautan yang tidak berisi teks sehingga tidak perlu diunduh
+var skipExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".ico": true,
+	".css": true, ".js": true, ".json": true, ".zip": true, ".gz": true, ".tar": true, ".mp3": true,
+	".mp4": true, ".webm": true, ".woff": true, ".woff2": true, ".ttf": true, ".exe": true, ".dmg": true,
+}
+
+// Options mengatur perilaku Crawler
+type Options struct {
+	// UserAgent dikirim di setiap permintaan dan dipakai untuk memilih grup robots.txt
+	UserAgent string
+	// MaxDepth adalah jumlah maksimum tautan yang diikuti dari URL awal; 0 hanya mengambil URL awal
+	MaxDepth int
+	// MaxPages adalah jumlah maksimum halaman yang diunduh
+	MaxPages int
+	// Concurrency adalah jumlah permintaan yang berjalan bersamaan
+	Concurrency int
+	// AllowedDomains membatasi host yang boleh dikunjungi (termasuk subdomainnya). Jika kosong,
+	// hanya host dari URL awal dan sitemap yang dikunjungi.
+	AllowedDomains []string
+	// Delay adalah jeda minimum antarpermintaan ke host yang sama; Crawl-delay robots.txt
+	// dipakai jika lebih besar
+	Delay time.Duration
+	// Validators mengembalikan ETag dan Last-Modified dari halaman yang pernah diambil agar
+	// halaman yang tidak berubah dapat dilewati dengan permintaan bersyarat (304 Not Modified).
+	// Respons 304 tidak berisi tautan, sehingga permintaan bersyarat hanya dikirim untuk halaman
+	// di MaxDepth yang tautannya memang tidak diikuti.
+	Validators func(ctx context.Context, pageURL string) (etag, lastModified string)
+}
+
+// Page adalah halaman yang berhasil diunduh dan diekstrak
+type Page struct {
+	URL      string
+	Depth    int
+	Title    string
+	Text     string
+	Format   string
+	MIMEType string
+	ETag     string
+	// LastModified diambil dari header Last-Modified atau <lastmod> sitemap, nol jika tidak ada
+	LastModified time.Time
+}
+
+// Stats merangkum hasil crawling
+type Stats struct {
+	Discovered  int `json:"discovered"`
+	Fetched     int `json:"fetched"`
+	NotModified int `json:"not_modified"`
+	Skipped     int `json:"skipped"`
+	Failed      int `json:"failed"`
+}
+
+// Crawler mengunduh halaman web dari URL awal atau sitemap dengan mematuhi robots.txt,
+// batas kedalaman, batas domain dan batas konkurensi
+type Crawler struct {
+	client *http.Client
+	opts   Options
+
+	mu          sync.Mutex
+	robots      map[string]*robotsRules
+	nextRequest map[string]time.Time
+	stats       Stats
+}
+
+// task adalah URL yang dijadwalkan untuk diunduh
+type task struct {
+	url          string
+	depth        int
+	lastModified time.Time
+}
+
+// New membuat Crawler baru
+func New(opts Options) *Crawler {
+	if opts.UserAgent == "" {
+		opts.UserAgent = "rag-chat-bot-crawler/1.0"
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = 500 // Default value
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4 // Default value
+	}
+	if opts.MaxDepth < 0 {
+		opts.MaxDepth = 0
+	}
+
+	return &Crawler{
+		client:      &http.Client{Timeout: 30 * time.Second},
+		opts:        opts,
+		robots:      make(map[string]*robotsRules),
+		nextRequest: make(map[string]time.Time),
+	}
+}
+
+// Crawl mengunduh URL awal dan semua URL dari sitemaps, lalu mengikuti tautan halaman HTML
+// sampai MaxDepth. Setiap halaman diteruskan ke visit; error dari visit dicatat dan tidak
+// menghentikan crawling. progress (boleh nil) dipanggil setelah setiap halaman dengan jumlah
+// halaman yang sudah diproses dan yang dijadwalkan.
+func (c *Crawler) Crawl(ctx context.Context, seeds, sitemaps []string, visit func(ctx context.Context, page *Page) error, progress func(done, total int)) (Stats, error) {
+	if progress == nil {
+		progress = func(int, int) {}
+	}
+
+	allowed := make(map[string]bool)
+	for _, domain := range c.opts.AllowedDomains {
+		allowed[strings.ToLower(strings.TrimPrefix(domain, "www."))] = true
+	}
+	if len(allowed) == 0 {
+		for _, raw := range append(append([]string{}, seeds...), sitemaps...) {
+			if u, err := url.Parse(raw); err == nil && u.Host != "" {
+				allowed[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")] = true
+			}
+		}
+	}
+	inScope := func(u *url.URL) bool {
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		for domain := range allowed {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+		return false
+	}
+
+	seen := make(map[string]bool)
+	var level []task
+	schedule := func(raw string, depth int, lastModified time.Time) {
+		u, err := normalizeURL(raw)
+		if err != nil || !inScope(u) || skipExtensions[strings.ToLower(path.Ext(u.Path))] {
+			return
+		}
+		key := u.String()
+		if seen[key] || len(seen) >= c.opts.MaxPages {
+			return
+		}
+		seen[key] = true
+		level = append(level, task{url: key, depth: depth, lastModified: lastModified})
+	}
+
+	for _, seed := range seeds {
+		schedule(seed, 0, time.Time{})
+	}
+	for _, entry := range c.sitemapEntries(ctx, sitemaps) {
+		schedule(entry.URL, 0, entry.LastModified)
+	}
+
+	// Crawling per tingkat kedalaman (BFS); setiap tingkat diunduh secara paralel
+	done := 0
+	for depth := 0; len(level) > 0 && ctx.Err() == nil; depth++ {
+		current := level
+		level = nil
+
+		var mu sync.Mutex
+		var links []task
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, c.opts.Concurrency)
+
+		for _, t := range current {
+			if ctx.Err() != nil {
+				break
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(t task) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				found := c.fetch(ctx, t, inScope, visit)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if t.depth < c.opts.MaxDepth {
+					for _, link := range found {
+						links = append(links, task{url: link, depth: t.depth + 1})
+					}
+				}
+				done++
+				progress(done, len(seen))
+			}(t)
+		}
+		wg.Wait()
+
+		for _, link := range links {
+			schedule(link.url, link.depth, time.Time{})
+		}
+	}
+
+	c.mu.Lock()
+	stats := c.stats
+	c.mu.Unlock()
+	stats.Discovered = len(seen)
+	return stats, ctx.Err()
+}
+
+// fetch mengunduh satu halaman, meneruskannya ke visit, dan mengembalikan tautan di dalamnya.
+// Halaman yang di-redirect ke luar inScope dilewati.
+func (c *Crawler) fetch(ctx context.Context, t task, inScope func(*url.URL) bool, visit func(ctx context.Context, page *Page) error) []string {
+	u, _ := url.Parse(t.url)
+
+	rules := c.robotsFor(ctx, u)
+	if !rules.allowed(u.RequestURI()) {
+		c.count(func(s *Stats) { s.Skipped++ })
+		return nil
+	}
+	if err := c.wait(ctx, u.Host, rules.crawlDelay); err != nil {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		c.count(func(s *Stats) { s.Failed++ })
+		return nil
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+	if c.opts.Validators != nil && t.depth >= c.opts.MaxDepth {
+		etag, lastModified := c.opts.Validators(ctx, t.url)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Printf("Error fetching %s: %v", t.url, err)
+		c.count(func(s *Stats) { s.Failed++ })
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		c.count(func(s *Stats) { s.NotModified++ })
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Error fetching %s: status %d", t.url, resp.StatusCode)
+		c.count(func(s *Stats) { s.Failed++ })
+		return nil
+	}
+
+	// Pakai URL setelah redirect sebagai identitas halaman
+	final := resp.Request.URL
+	if !inScope(final) {
+		log.Printf("Skipping %s: redirected out of scope to %s", t.url, final)
+		c.count(func(s *Stats) { s.Skipped++ })
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		log.Printf("Error reading %s: %v", t.url, err)
+		c.count(func(s *Stats) { s.Failed++ })
+		return nil
+	}
+
+	pageURL := t.url
+	if normalized, err := normalizeURL(final.String()); err == nil {
+		pageURL = normalized.String()
+	}
+
+	var links []string
+	contentType := resp.Header.Get("Content-Type")
+	if extract.DetectType(final.Path, contentType, body) == extract.MIMEHTML {
+		hrefs, base := extract.HTMLLinks(body)
+		links = resolveLinks(final, base, hrefs)
+	}
+
+	result, err := extract.Extract(final.Path, contentType, body)
+	if err != nil {
+		c.count(func(s *Stats) { s.Skipped++ })
+		return links
+	}
+
+	page := &Page{
+		URL:          pageURL,
+		Depth:        t.depth,
+		Title:        result.Title,
+		Text:         result.Text,
+		Format:       result.Format,
+		MIMEType:     result.MIMEType,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: t.lastModified,
+	}
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		page.LastModified = lastModified
+	}
+
+	if err := visit(ctx, page); err != nil {
+		log.Printf("Error ingesting %s: %v", pageURL, err)
+		c.count(func(s *Stats) { s.Failed++ })
+		return links
+	}
+	c.count(func(s *Stats) { s.Fetched++ })
+	return links
+}
+
+// count memperbarui statistik crawling
+func (c *Crawler) count(update func(*Stats)) {
+	c.mu.Lock()
+	update(&c.stats)
+	c.mu.Unlock()
+}
+
+// wait menunggu giliran permintaan berikutnya ke host agar tidak membebani server
+func (c *Crawler) wait(ctx context.Context, host string, crawlDelay time.Duration) error {
+	delay := max(c.opts.Delay, crawlDelay)
+
+	c.mu.Lock()
+	now := time.Now()
+	at := c.nextRequest[host]
+	if at.Before(now) {
+		at = now
+	}
+	c.nextRequest[host] = at.Add(delay)
+	c.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// robotsFor mengambil aturan robots.txt host (sekali per host). Sesuai RFC 9309, robots.txt
+// yang tidak ada (4xx) berarti semua boleh, sedangkan error server berarti semua dilarang.
+func (c *Crawler) robotsFor(ctx context.Context, u *url.URL) *robotsRules {
+	key := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	rules, ok := c.robots[key]
+	c.mu.Unlock()
+	if ok {
+		return rules
+	}
+
+	rules = &robotsRules{}
+	body, status, err := c.get(ctx, key+"/robots.txt")
+	switch {
+	case err != nil || status >= http.StatusInternalServerError:
+		log.Printf("Error fetching %s/robots.txt, skipping host: status %d, %v", key, status, err)
+		rules = &robotsRules{rules: []robotsRule{{pattern: "/", allow: false}}}
+	case status == http.StatusOK:
+		rules = parseRobots(body, c.opts.UserAgent)
+	}
+
+	c.mu.Lock()
+	c.robots[key] = rules
+	c.mu.Unlock()
+	return rules
+}
+
+// sitemapEntries mengunduh sitemap (termasuk sitemap index bersarang) dan mengembalikan URL-nya
+func (c *Crawler) sitemapEntries(ctx context.Context, sitemaps []string) []sitemapEntry {
+	var entries []sitemapEntry
+	seen := make(map[string]bool)
+	queue := append([]string{}, sitemaps...)
+
+	for len(queue) > 0 && len(entries) < c.opts.MaxPages && ctx.Err() == nil {
+		sitemapURL := queue[0]
+		queue = queue[1:]
+		if seen[sitemapURL] {
+			continue
+		}
+		seen[sitemapURL] = true
+
+		body, status, err := c.get(ctx, sitemapURL)
+		if err != nil || status != http.StatusOK {
+			log.Printf("Error fetching sitemap %s: status %d, %v", sitemapURL, status, err)
+			continue
+		}
+
+		found, nested, err := parseSitemap(body)
+		if err != nil {
+			log.Printf("Error parsing sitemap %s: %v", sitemapURL, err)
+			continue
+		}
+		entries = append(entries, found...)
+		queue = append(queue, nested...)
+	}
+	return entries
+}
+
+// get mengunduh URL tanpa mengikuti aturan robots.txt, untuk robots.txt dan sitemap
+func (c *Crawler) get(ctx context.Context, rawURL string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+// normalizeURL menyeragamkan URL agar halaman yang sama tidak diunduh dua kali: skema dan
+// host huruf kecil, tanpa fragmen, dan path kosong menjadi "/"
+func normalizeURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme: %s", u.Scheme)
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u, nil
+}
+
+// resolveLinks mengubah href relatif menjadi URL absolut terhadap halaman atau <base>
+func resolveLinks(page *url.URL, base string, hrefs []string) []string {
+	baseURL := page
+	if base != "" {
+		if b, err := page.Parse(base); err == nil {
+			baseURL = b
+		}
+	}
+
+	links := make([]string, 0, len(hrefs))
+	for _, href := range hrefs {
+		if strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") || strings.HasPrefix(strings.ToLower(href), "mailto:") {
+			continue
+		}
+		if u, err := baseURL.Parse(href); err == nil {
+			links = append(links, u.String())
+		}
+	}
+	return links
+}
@@ -0,0 +1,221 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSite adalah situs httptest dengan halaman HTML dan pencatat permintaan
+type testSite struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+}
+
+// newTestSite membuat situs dengan routes berupa path → handler. Halaman tanpa handler
+// mengembalikan 404.
+func newTestSite(t *testing.T, routes map[string]http.HandlerFunc) *testSite {
+	site := &testSite{}
+	site.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site.mu.Lock()
+		site.requests = append(site.requests, r.URL.Path)
+		site.mu.Unlock()
+		if handler, ok := routes[r.URL.Path]; ok {
+			handler(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(site.Close)
+	return site
+}
+
+// requested memeriksa apakah path pernah diminta
+func (s *testSite) requested(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.requests, path)
+}
+
+// htmlPage mengembalikan handler halaman HTML dengan judul dan tautan ke links
+func htmlPage(title string, links ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body strings.Builder
+		fmt.Fprintf(&body, "<html><head><title>%s</title></head><body><main><h1>%s</h1><p>Isi halaman %s.</p>", title, title, title)
+		for _, link := range links {
+			fmt.Fprintf(&body, `<a href="%s">%s</a>`, link, link)
+		}
+		body.WriteString("</main></body></html>")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(body.String()))
+	}
+}
+
+// crawl menjalankan Crawler dan mengembalikan halaman yang dikunjungi, terurut menurut URL
+func crawl(t *testing.T, opts Options, seeds, sitemaps []string) ([]*Page, Stats) {
+	t.Helper()
+	var mu sync.Mutex
+	var pages []*Page
+	stats, err := New(opts).Crawl(context.Background(), seeds, sitemaps, func(ctx context.Context, page *Page) error {
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].URL < pages[j].URL })
+	return pages, stats
+}
+
+// pagePaths mengembalikan path URL setiap halaman
+func pagePaths(pages []*Page) []string {
+	paths := make([]string, len(pages))
+	for i, page := range pages {
+		u, _ := url.Parse(page.URL)
+		paths[i] = u.Path
+	}
+	return paths
+}
+
+func TestCrawlScopeAndRobots(t *testing.T) {
+	// Situs lain dijangkau melalui "localhost" agar host-nya berbeda dari 127.0.0.1
+	external := newTestSite(t, map[string]http.HandlerFunc{
+		"/ext":     htmlPage("Eksternal"),
+		"/landing": htmlPage("Landing"),
+	})
+	externalURL := strings.Replace(external.URL, "127.0.0.1", "localhost", 1)
+
+	site := newTestSite(t, map[string]http.HandlerFunc{
+		"/robots.txt": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("User-agent: bot\nDisallow: /\n\nUser-agent: *\nDisallow: /private\n"))
+		},
+		"/":          htmlPage("Beranda", "/a", "/private/x", externalURL+"/ext", "/logo.png", "/redirect", "#top"),
+		"/a":         htmlPage("A", "/b", "/"),
+		"/b":         htmlPage("B"),
+		"/private/x": htmlPage("Rahasia"),
+		"/redirect": func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, externalURL+"/landing", http.StatusFound)
+		},
+	})
+
+	pages, stats := crawl(t, Options{UserAgent: "rag-chat-bot-crawler/1.0", MaxDepth: 1}, []string{site.URL}, nil)
+
+	if got, want := pagePaths(pages), []string{"/", "/a"}; !slices.Equal(got, want) {
+		t.Errorf("visited %q, want %q", got, want)
+	}
+	want := Stats{Discovered: 4, Fetched: 2, Skipped: 2}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	for _, path := range []string{"/private/x", "/b", "/logo.png"} {
+		if site.requested(path) {
+			t.Errorf("%s was requested", path)
+		}
+	}
+	if external.requested("/ext") {
+		t.Errorf("out of scope link was requested")
+	}
+	if pages[0].Title != "Beranda" || !strings.Contains(pages[0].Text, "Isi halaman Beranda") {
+		t.Errorf("page = %+v", pages[0])
+	}
+}
+
+func TestCrawlNotModified(t *testing.T) {
+	conditional := func(page http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			page(w, r)
+		}
+	}
+	site := newTestSite(t, map[string]http.HandlerFunc{
+		"/":    conditional(htmlPage("Beranda", "/a", "/new")),
+		"/a":   conditional(htmlPage("A", "/deep")),
+		"/new": conditional(htmlPage("Baru")),
+	})
+
+	// Semua halaman pernah diambil; hanya halaman di MaxDepth yang diminta secara bersyarat
+	// agar tautan dari halaman yang tidak berubah tetap diikuti
+	opts := Options{
+		MaxDepth: 1,
+		Validators: func(ctx context.Context, pageURL string) (string, string) {
+			if strings.HasSuffix(pageURL, "/new") {
+				return "", ""
+			}
+			return `"v1"`, ""
+		},
+	}
+	pages, stats := crawl(t, opts, []string{site.URL + "/"}, nil)
+
+	if got, want := pagePaths(pages), []string{"/", "/new"}; !slices.Equal(got, want) {
+		t.Errorf("visited %q, want %q", got, want)
+	}
+	want := Stats{Discovered: 3, Fetched: 2, NotModified: 1}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	if pages[0].ETag != `"v1"` {
+		t.Errorf("ETag = %q, want %q", pages[0].ETag, `"v1"`)
+	}
+}
+
+func TestCrawlSitemap(t *testing.T) {
+	var site *testSite
+	site = newTestSite(t, map[string]http.HandlerFunc{
+		"/sitemap-index.xml": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s/sitemap.xml</loc></sitemap></sitemapindex>`, site.URL)
+		},
+		"/sitemap.xml": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<urlset><url><loc>%[1]s/a</loc><lastmod>2024-03-01</lastmod></url><url><loc>%[1]s/b</loc></url></urlset>`, site.URL)
+		},
+		"/a": htmlPage("A", "/c"),
+		"/b": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Last-Modified", "Tue, 05 Mar 2024 10:00:00 GMT")
+			htmlPage("B")(w, r)
+		},
+		"/c": htmlPage("C"),
+	})
+
+	pages, stats := crawl(t, Options{}, nil, []string{site.URL + "/sitemap-index.xml"})
+
+	if got, want := pagePaths(pages), []string{"/a", "/b"}; !slices.Equal(got, want) {
+		t.Fatalf("visited %q, want %q", got, want)
+	}
+	if stats.Discovered != 2 || stats.Fetched != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !pages[0].LastModified.Equal(want) {
+		t.Errorf("sitemap lastmod = %s, want %s", pages[0].LastModified, want)
+	}
+	if want := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC); !pages[1].LastModified.Equal(want) {
+		t.Errorf("Last-Modified = %s, want %s", pages[1].LastModified, want)
+	}
+}
+
+func TestCrawlRobotsServerError(t *testing.T) {
+	site := newTestSite(t, map[string]http.HandlerFunc{
+		"/robots.txt": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		"/": htmlPage("Beranda"),
+	})
+
+	pages, stats := crawl(t, Options{}, []string{site.URL}, nil)
+	if len(pages) != 0 || stats.Skipped != 1 {
+		t.Errorf("visited %d pages with stats %+v, want host skipped", len(pages), stats)
+	}
+}
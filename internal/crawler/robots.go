@@ -0,0 +1,155 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsRules adalah aturan robots.txt yang berlaku untuk user agent crawler
+type robotsRules struct {
+	rules []robotsRule
+	// crawlDelay adalah jeda antarpermintaan yang diminta situs, nol jika tidak ada
+	crawlDelay time.Duration
+}
+
+// robotsRule adalah satu baris Allow atau Disallow
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// parseRobots mengurai robots.txt dan mengambil aturan untuk userAgent. Sesuai RFC 9309, grup
+// yang berlaku adalah grup yang nama agent-nya sama dengan product token userAgent (tanpa
+// membedakan huruf besar/kecil), atau grup "*" jika tidak ada; beberapa grup yang cocok
+// digabungkan.
+func parseRobots(data []byte, userAgent string) *robotsRules {
+	agent := productToken(userAgent)
+
+	type group struct {
+		agents []string
+		rules  []robotsRule
+		delay  time.Duration
+	}
+	var groups []*group
+	var current *group
+	inAgents := false
+	result := &robotsRules{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// User-agent berturut-turut berbagi satu grup
+			if !inAgents {
+				current = &group{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, productToken(value))
+			inAgents = true
+			continue
+		case "allow", "disallow":
+			if current != nil && (value != "" || key == "allow") {
+				current.rules = append(current.rules, robotsRule{pattern: value, allow: key == "allow"})
+			}
+		case "crawl-delay":
+			if current != nil {
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					current.delay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+		inAgents = false
+	}
+
+	var matched, wildcard []*group
+	for _, g := range groups {
+		switch {
+		case agent != "" && slices.Contains(g.agents, agent):
+			matched = append(matched, g)
+		case slices.Contains(g.agents, "*"):
+			wildcard = append(wildcard, g)
+		}
+	}
+	if len(matched) == 0 {
+		matched = wildcard
+	}
+	for _, g := range matched {
+		result.rules = append(result.rules, g.rules...)
+		result.crawlDelay = max(result.crawlDelay, g.delay)
+	}
+	return result
+}
+
+// productToken mengambil product token dari user agent atau baris User-agent, misalnya
+// "examplebot" dari "ExampleBot/1.0 (+https://example.com)"
+func productToken(userAgent string) string {
+	token := strings.ToLower(strings.TrimSpace(userAgent))
+	if i := strings.IndexAny(token, "/ \t"); i >= 0 {
+		token = token[:i]
+	}
+	return token
+}
+
+// allowed memeriksa apakah path (termasuk query) boleh diambil. Aturan dengan pola terpanjang
+// yang cocok menang, dan Allow menang jika panjangnya sama.
+func (r *robotsRules) allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if rule.pattern == "" {
+			continue
+		}
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > best || (len(rule.pattern) == best && rule.allow) {
+			best, allow = len(rule.pattern), rule.allow
+		}
+	}
+	return allow
+}
+
+// robotsMatch mencocokkan path dengan pola robots.txt yang mendukung * dan $ di akhir pola
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for _, part := range parts[1:] {
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+
+	if anchored {
+		if len(parts) > 1 {
+			return strings.HasSuffix(path, parts[len(parts)-1])
+		}
+		return pos == len(path)
+	}
+	return true
+}
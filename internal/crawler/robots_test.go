@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRobots(t *testing.T) {
+	const robots = `
+# Aturan untuk semua crawler
+User-agent: *
+Disallow: /private
+Crawl-delay: 1
+
+User-agent: bot
+Disallow: /
+
+User-agent: Other-Bot
+User-agent: ExampleBot/2.0
+Disallow: /drafts
+Allow: /drafts/public
+Crawl-delay: 0.5
+
+User-agent: examplebot
+Disallow: /*.pdf$
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+	tests := []struct {
+		name      string
+		userAgent string
+		path      string
+		want      bool
+	}{
+		{"wildcard disallow", "rag-chat-bot-crawler/1.0", "/private/page", false},
+		{"wildcard allow", "rag-chat-bot-crawler/1.0", "/docs", true},
+		{"substring agent does not match", "examplebot-crawler/1.0", "/docs", true},
+		{"matched group replaces wildcard", "ExampleBot/1.0", "/private/page", true},
+		{"case insensitive product token", "EXAMPLEBOT", "/drafts/a", false},
+		{"longest match allows", "ExampleBot/1.0 (+https://example.com)", "/drafts/public/a", true},
+		{"matching groups are combined", "examplebot", "/files/a.pdf", false},
+		{"anchored pattern", "examplebot", "/files/a.pdf?download=1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := parseRobots([]byte(robots), tt.userAgent)
+			if got := rules.allowed(tt.path); got != tt.want {
+				t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if got := parseRobots([]byte(robots), "rag-chat-bot-crawler").crawlDelay; got != time.Second {
+		t.Errorf("wildcard crawl delay = %s, want 1s", got)
+	}
+	if got := parseRobots([]byte(robots), "examplebot").crawlDelay; got != 500*time.Millisecond {
+		t.Errorf("examplebot crawl delay = %s, want 500ms", got)
+	}
+}
+
+func TestRobotsMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/docs", "/docs/intro", true},
+		{"/docs", "/blog", false},
+		{"/*.php", "/index.php?x=1", true},
+		{"/*.php$", "/index.php?x=1", false},
+		{"/*.php$", "/a/index.php", true},
+		{"/a*b*c", "/a-x-b-y-c-z", true},
+		{"/a*b*c", "/a-x-c-y-b", false},
+		{"/exact$", "/exact", true},
+		{"/exact$", "/exact/more", false},
+	}
+
+	for _, tt := range tests {
+		if got := robotsMatch(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("robotsMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
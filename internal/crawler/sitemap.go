@@ -0,0 +1,72 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// sitemapEntry adalah satu URL dari sitemap beserta waktu perubahan terakhirnya
+type sitemapEntry struct {
+	URL          string
+	LastModified time.Time
+}
+
+// sitemapDocument mencakup <urlset> dan <sitemapindex>
+type sitemapDocument struct {
+	XMLName xml.Name
+	URLs    []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// parseSitemap mengurai sitemap (boleh terkompresi gzip) dan mengembalikan URL halaman serta
+// URL sitemap lain jika dokumen adalah sitemap index
+func parseSitemap(data []byte) (entries []sitemapEntry, sitemaps []string, err error) {
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		if data, err = io.ReadAll(io.LimitReader(zr, maxBodyBytes)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var doc sitemapDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	for _, u := range doc.URLs {
+		loc := strings.TrimSpace(u.Loc)
+		if loc == "" {
+			continue
+		}
+		entries = append(entries, sitemapEntry{URL: loc, LastModified: parseSitemapTime(u.LastMod)})
+	}
+	for _, s := range doc.Sitemaps {
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			sitemaps = append(sitemaps, loc)
+		}
+	}
+	return entries, sitemaps, nil
+}
+
+// parseSitemapTime membaca <lastmod> dalam format W3C Datetime
+func parseSitemapTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
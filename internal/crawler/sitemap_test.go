@@ -0,0 +1,69 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSitemap(t *testing.T) {
+	urlset := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc> https://example.com/a </loc><lastmod>2024-03-01</lastmod></url>
+  <url><loc>https://example.com/b</loc><lastmod>2024-03-02T10:30:00+07:00</lastmod></url>
+  <url><loc>https://example.com/c</loc><lastmod>kemarin</lastmod></url>
+  <url><loc></loc></url>
+</urlset>`
+	index := `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-1.xml</loc></sitemap>
+  <sitemap><loc>https://example.com/sitemap-2.xml.gz</loc></sitemap>
+</sitemapindex>`
+
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	zw.Write([]byte(urlset))
+	zw.Close()
+
+	entries := []sitemapEntry{
+		{URL: "https://example.com/a", LastModified: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{URL: "https://example.com/b", LastModified: time.Date(2024, 3, 2, 3, 30, 0, 0, time.UTC)},
+		{URL: "https://example.com/c"},
+	}
+
+	tests := []struct {
+		name         string
+		data         []byte
+		wantEntries  []sitemapEntry
+		wantSitemaps []string
+		wantErr      bool
+	}{
+		{"urlset", []byte(urlset), entries, nil, false},
+		{"gzip", gzipped.Bytes(), entries, nil, false},
+		{"index", []byte(index), nil, []string{"https://example.com/sitemap-1.xml", "https://example.com/sitemap-2.xml.gz"}, false},
+		{"invalid", []byte("<urlset><url>"), nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotEntries, gotSitemaps, err := parseSitemap(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSitemap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(gotEntries) != len(tt.wantEntries) {
+				t.Fatalf("entries = %+v, want %+v", gotEntries, tt.wantEntries)
+			}
+			for i, entry := range gotEntries {
+				want := tt.wantEntries[i]
+				if entry.URL != want.URL || !entry.LastModified.Equal(want.LastModified) {
+					t.Errorf("entry %d = %+v, want %+v", i, entry, want)
+				}
+			}
+			if !reflect.DeepEqual(gotSitemaps, tt.wantSitemaps) {
+				t.Errorf("sitemaps = %q, want %q", gotSitemaps, tt.wantSitemaps)
+			}
+		})
+	}
+}
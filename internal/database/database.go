@@ -3,11 +3,17 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"rag-chat-bot/internal/config"
 	"rag-chat-bot/internal/model"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/lib/pq" // Driver PostgreSQL
 )
@@ -48,96 +54,526 @@ func (db *PostgresDB) Close() {
 	}
 }
 
-// SaveDocument menyimpan dokumen baru ke database
-func (db *PostgresDB) SaveDocument(ctx context.Context, doc *model.Document) (int, error) {
-	var docID int
+// EmbeddingDimension mengembalikan dimensi kolom vektor embedding di database
+func (db *PostgresDB) EmbeddingDimension(ctx context.Context) (int, error) {
+	var dimension int
+	// Untuk tipe vector, atttypmod berisi dimensi yang dideklarasikan
+	err := db.pool.QueryRow(ctx, `
+		SELECT atttypmod FROM pg_attribute
+		WHERE attrelid = 'document_chunks'::regclass AND attname = 'embedding'
+	`).Scan(&dimension)
+	if err != nil {
+		return 0, fmt.Errorf("error reading embedding dimension: %w", err)
+	}
+	return dimension, nil
+}
 
+// SaveDocuments menyimpan dokumen baru beserta potongan dan embedding-nya dalam satu transaksi.
+// chunks[i] adalah potongan milik docs[i]. Jika salah satu penyisipan gagal, tidak ada dokumen
+// yang tersimpan sehingga tidak ada dokumen tanpa embedding yang tidak terlihat oleh pencarian.
+func (db *PostgresDB) SaveDocuments(ctx context.Context, docs []*model.Document, chunks [][]*model.DocumentChunk) ([]int, error) {
 	tx, err := db.pool.Begin(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("error starting transaction: %w", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
-	defer func() {
+	defer tx.Rollback(ctx)
+
+	docIDs := make([]int, len(docs))
+	for i, doc := range docs {
+		// Menyimpan dokumen
+		err := tx.QueryRow(ctx,
+			"INSERT INTO documents (title, content, metadata, content_hash) VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING id",
+			doc.Title, doc.Content, doc.Metadata, doc.ContentHash).Scan(&docIDs[i])
 		if err != nil {
-			tx.Rollback(ctx)
+			if isDuplicateContent(err) {
+				return nil, ErrDuplicateDocument
+			}
+			return nil, fmt.Errorf("error inserting document: %w", err)
 		}
-	}()
 
-	// Menyimpan dokumen
-	err = tx.QueryRow(ctx,
-		"INSERT INTO documents (title, content, metadata) VALUES ($1, $2, $3) RETURNING id",
-		doc.Title, doc.Content, doc.Metadata).Scan(&docID)
-	if err != nil {
-		return 0, fmt.Errorf("error inserting document: %w", err)
+		for _, chunk := range chunks[i] {
+			chunk.DocumentID = docIDs[i]
+		}
+		if err := insertChunks(ctx, tx, docIDs[i], chunks[i]); err != nil {
+			return nil, err
+		}
 	}
 
 	// Commit transaksi
-	if err = tx.Commit(ctx); err != nil {
-		return 0, fmt.Errorf("error committing transaction: %w", err)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
-	return docID, nil
+	return docIDs, nil
 }
 
-// SaveEmbedding menyimpan embedding untuk dokumen ke database
-func (db *PostgresDB) SaveEmbedding(ctx context.Context, docID int, embedding []float32) error {
-	// Convert float32 slice to string representation of array
-	vectorStr := "["
+// vectorToString mengubah slice float32 menjadi representasi teks tipe vector pgvector
+func vectorToString(embedding []float32) string {
+	var sb strings.Builder
+	sb.WriteString("[")
 	for i, v := range embedding {
 		if i > 0 {
-			vectorStr += ","
+			sb.WriteString(",")
 		}
-		vectorStr += fmt.Sprintf("%f", v)
+		sb.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
 	}
-	vectorStr += "]"
+	sb.WriteString("]")
+	return sb.String()
+}
 
-	_, err := db.pool.Exec(ctx,
-		"INSERT INTO document_embeddings (document_id, embedding) VALUES ($1, $2::vector)",
-		docID, vectorStr)
+// insertChunks menyisipkan potongan dokumen di dalam transaksi tx menggunakan satu batch
+func insertChunks(ctx context.Context, tx pgx.Tx, docID int, chunks []*model.DocumentChunk) error {
+	batch := &pgx.Batch{}
+	for _, chunk := range chunks {
+		metadata := chunk.Metadata
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		batch.Queue(`
+			INSERT INTO document_chunks (document_id, chunk_index, content, start_offset, end_offset, metadata, embedding, content_hash)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::vector, NULLIF($8, ''))
+		`, docID, chunk.ChunkIndex, chunk.Content, chunk.StartOffset, chunk.EndOffset, metadata, vectorToString(chunk.Embedding), chunk.ContentHash)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("error inserting chunks: %w", err)
+	}
+	return nil
+}
+
+// ErrDocumentNotFound dikembalikan saat dokumen dengan ID yang diminta tidak ada
+var ErrDocumentNotFound = errors.New("document not found")
+
+// ErrDuplicateDocument dikembalikan saat dokumen aktif lain sudah memiliki konten yang sama
+var ErrDuplicateDocument = errors.New("document with the same content already exists")
+
+// isDuplicateContent memeriksa apakah err adalah pelanggaran indeks unik content_hash
+func isDuplicateContent(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_documents_content_hash"
+}
+
+// documentColumns adalah kolom yang dipilih saat membaca dokumen lengkap dengan scanDocument
+const documentColumns = `id, title, content, metadata, COALESCE(content_hash, ''), version,
+	previous_version_id, superseded_at, created_at, updated_at`
+
+// scanDocument membaca satu baris documentColumns
+func scanDocument(row pgx.Row) (*model.Document, error) {
+	var doc model.Document
+	err := row.Scan(&doc.ID, &doc.Title, &doc.Content, &doc.Metadata, &doc.ContentHash, &doc.Version,
+		&doc.PreviousVersionID, &doc.SupersededAt, &doc.CreatedAt, &doc.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// DocumentListOptions mengatur pencarian dan paginasi daftar dokumen
+type DocumentListOptions struct {
+	// Title mencari dokumen yang judulnya mengandung teks ini (tidak peka huruf besar/kecil)
+	Title string
+	// Filter membatasi dokumen berdasarkan metadata
+	Filter model.MetadataFilter
+	// CreatedAfter dan CreatedBefore membatasi waktu pembuatan dokumen, diabaikan jika nol
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Limit         int
+	Offset        int
+}
+
+// ListDocuments mengambil ringkasan dokumen terbaru yang sesuai dengan opsi beserta jumlah totalnya
+func (db *PostgresDB) ListDocuments(ctx context.Context, opts DocumentListOptions) ([]*model.DocumentSummary, int, error) {
+	// Versi lama yang sudah digantikan tidak ditampilkan
+	conditions := []string{"d.superseded_at IS NULL"}
+	var args []interface{}
+
+	if opts.Title != "" {
+		args = append(args, "%"+opts.Title+"%")
+		conditions = append(conditions, fmt.Sprintf("d.title ILIKE $%d", len(args)))
+	}
+	if !opts.CreatedAfter.IsZero() {
+		args = append(args, opts.CreatedAfter)
+		conditions = append(conditions, fmt.Sprintf("d.created_at >= $%d", len(args)))
+	}
+	if !opts.CreatedBefore.IsZero() {
+		args = append(args, opts.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("d.created_at < $%d", len(args)))
+	}
+
+	filter, args, err := metadataFilterSQL("d.metadata", opts.Filter, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	conditions = append(conditions, filter)
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := db.pool.QueryRow(ctx, "SELECT COUNT(*) FROM documents d WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting documents: %w", err)
+	}
+
+	args = append(args, opts.Limit, opts.Offset)
+	rows, err := db.pool.Query(ctx, fmt.Sprintf(`
+		SELECT d.id, d.title, d.metadata, d.version, d.created_at, d.updated_at,
+		       (SELECT COUNT(*) FROM document_chunks c WHERE c.document_id = d.id) AS chunk_count
+		FROM documents d
+		WHERE %s
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error querying documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []*model.DocumentSummary
+	for rows.Next() {
+		var doc model.DocumentSummary
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Metadata, &doc.Version, &doc.CreatedAt, &doc.UpdatedAt, &doc.ChunkCount); err != nil {
+			return nil, 0, fmt.Errorf("error scanning document row: %w", err)
+		}
+		documents = append(documents, &doc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return documents, total, nil
+}
+
+// GetDocument mengambil dokumen lengkap berdasarkan ID
+func (db *PostgresDB) GetDocument(ctx context.Context, id int) (*model.Document, error) {
+	doc, err := scanDocument(db.pool.QueryRow(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("error getting document: %w", err)
+	}
+	return doc, nil
+}
+
+// FindDocumentByMetadata mengambil dokumen aktif terbaru yang metadata key-nya bernilai value,
+// misalnya dokumen hasil crawling berdasarkan source_url
+func (db *PostgresDB) FindDocumentByMetadata(ctx context.Context, key, value string) (*model.Document, error) {
+	doc, err := scanDocument(db.pool.QueryRow(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE metadata @> jsonb_build_object($1::text, $2::text) AND superseded_at IS NULL
+		ORDER BY id DESC
+		LIMIT 1
+	`, key, value))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("error finding document by metadata: %w", err)
+	}
+	return doc, nil
+}
+
+// FindDocumentByContentHash mengambil dokumen aktif yang hash kontennya sama dengan hash
+func (db *PostgresDB) FindDocumentByContentHash(ctx context.Context, hash string) (*model.Document, error) {
+	doc, err := scanDocument(db.pool.QueryRow(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE content_hash = $1 AND superseded_at IS NULL
+	`, hash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("error finding document by content hash: %w", err)
+	}
+	return doc, nil
+}
+
+// SaveDocumentVersion menyimpan doc sebagai versi baru dari dokumen previousID dalam satu
+// transaksi: versi lama diberi superseded_at sehingga tidak muncul lagi di pencarian, lalu
+// dokumen baru disisipkan dengan nomor versi berikutnya. Jika chunks nil, potongan dan
+// embedding versi lama disalin karena kontennya sama.
+func (db *PostgresDB) SaveDocumentVersion(ctx context.Context, previousID int, doc *model.Document, chunks []*model.DocumentChunk) (int, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var previousVersion int
+	err = tx.QueryRow(ctx, `
+		UPDATE documents SET superseded_at = NOW()
+		WHERE id = $1 AND superseded_at IS NULL
+		RETURNING version
+	`, previousID).Scan(&previousVersion)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrDocumentNotFound
+		}
+		return 0, fmt.Errorf("error superseding document: %w", err)
+	}
+
+	doc.Version = previousVersion + 1
+	doc.PreviousVersionID = &previousID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO documents (title, content, metadata, content_hash, version, previous_version_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+		RETURNING id
+	`, doc.Title, doc.Content, doc.Metadata, doc.ContentHash, doc.Version, previousID).Scan(&doc.ID)
+	if err != nil {
+		if isDuplicateContent(err) {
+			return 0, ErrDuplicateDocument
+		}
+		return 0, fmt.Errorf("error inserting document: %w", err)
+	}
+
+	if chunks == nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO document_chunks (document_id, chunk_index, content, start_offset, end_offset, metadata, embedding, content_hash)
+			SELECT $1, chunk_index, content, start_offset, end_offset, metadata, embedding, content_hash
+			FROM document_chunks
+			WHERE document_id = $2
+		`, doc.ID, previousID)
+		if err != nil {
+			return 0, fmt.Errorf("error copying chunks: %w", err)
+		}
+	} else if err := insertChunks(ctx, tx, doc.ID, chunks); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return doc.ID, nil
+}
+
+// DocumentIDsByMetadata mengembalikan ID dokumen untuk setiap nilai metadata key, misalnya
+// source_path setiap berkas yang disinkronkan. Dokumen tanpa key tersebut tidak disertakan.
+func (db *PostgresDB) DocumentIDsByMetadata(ctx context.Context, key string) (map[string]int, error) {
+	rows, err := db.pool.Query(ctx, `
+		SELECT metadata ->> $1, id
+		FROM documents
+		WHERE metadata ->> $1 IS NOT NULL AND superseded_at IS NULL
+	`, key)
+	if err != nil {
+		return nil, fmt.Errorf("error querying documents by metadata: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int)
+	for rows.Next() {
+		var value string
+		var id int
+		if err := rows.Scan(&value, &id); err != nil {
+			return nil, fmt.Errorf("error scanning document row: %w", err)
+		}
+		ids[value] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateDocument memperbarui judul, konten dan metadata dokumen. Jika chunks tidak nil,
+// potongan lama diganti dengan chunks dalam transaksi yang sama sehingga pencarian tidak
+// pernah melihat dokumen tanpa potongan.
+func (db *PostgresDB) UpdateDocument(ctx context.Context, doc *model.Document, chunks []*model.DocumentChunk) error {
+	tx, err := db.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("error inserting embedding: %w", err)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE documents
+		SET title = $2, content = $3, metadata = $4, content_hash = NULLIF($5, ''), updated_at = NOW()
+		WHERE id = $1
+	`, doc.ID, doc.Title, doc.Content, doc.Metadata, doc.ContentHash)
+	if err != nil {
+		if isDuplicateContent(err) {
+			return ErrDuplicateDocument
+		}
+		return fmt.Errorf("error updating document: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDocumentNotFound
+	}
+
+	if chunks != nil {
+		if _, err := tx.Exec(ctx, "DELETE FROM document_chunks WHERE document_id = $1", doc.ID); err != nil {
+			return fmt.Errorf("error deleting chunks: %w", err)
+		}
+		if err := insertChunks(ctx, tx, doc.ID, chunks); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
 	}
 
 	return nil
 }
 
-// FindSimilarDocuments mencari dokumen yang serupa berdasarkan embedding kueri
-func (db *PostgresDB) FindSimilarDocuments(ctx context.Context, queryEmbedding []float32, limit int) ([]*model.DocumentWithScore, error) {
-	// Convert float32 slice to string representation of array
-	vectorStr := "["
-	for i, v := range queryEmbedding {
-		if i > 0 {
-			vectorStr += ","
+// FindDocumentsWithoutChunks mengambil dokumen yang belum memiliki potongan, misalnya dokumen
+// yang tersimpan sebelum penyimpanan dokumen dan embedding dilakukan dalam satu transaksi
+func (db *PostgresDB) FindDocumentsWithoutChunks(ctx context.Context, limit int) ([]*model.Document, error) {
+	rows, err := db.pool.Query(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents d
+		WHERE NOT EXISTS (SELECT 1 FROM document_chunks c WHERE c.document_id = d.id)
+		  AND superseded_at IS NULL
+		ORDER BY d.id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying documents without chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []*model.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning document row: %w", err)
 		}
-		vectorStr += fmt.Sprintf("%f", v)
+		documents = append(documents, doc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return documents, nil
+}
+
+// DeleteDocument menghapus dokumen; potongan dan embedding-nya ikut terhapus melalui ON DELETE CASCADE
+func (db *PostgresDB) DeleteDocument(ctx context.Context, id int) error {
+	tag, err := db.pool.Exec(ctx, "DELETE FROM documents WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("error deleting document: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDocumentNotFound
+	}
+	return nil
+}
+
+// chunkResultColumns adalah kolom yang dipilih oleh kueri pencarian potongan dokumen
+const chunkResultColumns = `
+	d.id, d.title, d.metadata, d.created_at,
+	c.id, c.chunk_index, c.content, c.start_offset, c.end_offset, c.metadata, COALESCE(c.content_hash, '')`
+
+// FindSimilarDocuments mencari potongan dokumen yang serupa berdasarkan embedding kueri
+// dan mengembalikannya bersama dokumen induknya. Hanya dokumen yang metadatanya memenuhi
+// filter yang diikutsertakan.
+func (db *PostgresDB) FindSimilarDocuments(ctx context.Context, queryEmbedding []float32, limit int, filter model.MetadataFilter) ([]*model.DocumentWithScore, error) {
+	where, args, err := metadataFilterSQL("d.metadata", filter, []interface{}{vectorToString(queryEmbedding), limit})
+	if err != nil {
+		return nil, err
 	}
-	vectorStr += "]"
 
 	rows, err := db.pool.Query(ctx, `
-		SELECT d.id, d.title, d.content, d.metadata, 
-		       1 - (e.embedding <=> $1::vector) as similarity_score
-		FROM document_embeddings e
-		JOIN documents d ON e.document_id = d.id
-		ORDER BY e.embedding <=> $1::vector
+		SELECT `+chunkResultColumns+`,
+		       1 - (c.embedding <=> $1::vector) AS vector_score,
+		       0::float8 AS keyword_score
+		FROM document_chunks c
+		JOIN documents d ON c.document_id = d.id
+		WHERE d.superseded_at IS NULL AND `+where+`
+		ORDER BY c.embedding <=> $1::vector
 		LIMIT $2
-	`, vectorStr, limit)
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying similar documents: %w", err)
 	}
+
+	results, err := scanChunkResults(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range results {
+		doc.Score = doc.VectorScore
+	}
+	return results, nil
+}
+
+// FindKeywordMatches mencari potongan dokumen dengan full-text search. Setiap kata pada kueri
+// cukup cocok salah satunya (OR), dan hasil diurutkan dengan ts_rank_cd yang dinormalisasi
+// dengan panjang dokumen (mirip BM25). Jika queryEmbedding diberikan, cosine similarity setiap
+// hasil juga dihitung. Seperti FindSimilarDocuments, hasil dibatasi dengan filter metadata.
+func (db *PostgresDB) FindKeywordMatches(ctx context.Context, query string, queryEmbedding []float32, limit int, filter model.MetadataFilter) ([]*model.DocumentWithScore, error) {
+	var vector interface{}
+	if queryEmbedding != nil {
+		vector = vectorToString(queryEmbedding)
+	}
+
+	where, args, err := metadataFilterSQL("d.metadata", filter, []interface{}{query, vector, limit})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.pool.Query(ctx, `
+		WITH q AS (
+			SELECT replace(plainto_tsquery('simple', $1)::text, '&', '|')::tsquery AS query
+		)
+		SELECT `+chunkResultColumns+`,
+		       COALESCE(1 - (c.embedding <=> $2::vector), 0) AS vector_score,
+		       ts_rank_cd(c.content_tsv, q.query, 1)::float8 AS keyword_score
+		FROM document_chunks c
+		JOIN documents d ON c.document_id = d.id
+		CROSS JOIN q
+		WHERE c.content_tsv @@ q.query AND d.superseded_at IS NULL AND `+where+`
+		ORDER BY keyword_score DESC
+		LIMIT $3
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying keyword matches: %w", err)
+	}
+
+	results, err := scanChunkResults(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range results {
+		doc.Score = doc.KeywordScore
+	}
+	return results, nil
+}
+
+// scanChunkResults membaca baris hasil pencarian potongan beserta dokumen induknya
+func scanChunkResults(rows pgx.Rows) ([]*model.DocumentWithScore, error) {
 	defer rows.Close()
 
 	var results []*model.DocumentWithScore
 
 	for rows.Next() {
 		var doc model.DocumentWithScore
+		var chunk model.DocumentChunk
 		var metadataJSON []byte
 
-		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Content, &metadataJSON, &doc.Score); err != nil {
+		if err := rows.Scan(&doc.ID, &doc.Title, &metadataJSON, &doc.CreatedAt,
+			&chunk.ID, &chunk.ChunkIndex, &chunk.Content, &chunk.StartOffset, &chunk.EndOffset, &chunk.Metadata, &chunk.ContentHash,
+			&doc.VectorScore, &doc.KeywordScore); err != nil {
 			return nil, fmt.Errorf("error scanning document row: %w", err)
 		}
 
-		// Parse metadata jika diperlukan
 		doc.Metadata = make(map[string]interface{})
-		// Jika ingin memproses metadata, tambahkan kode di sini
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &doc.Metadata); err != nil {
+				return nil, fmt.Errorf("error parsing document metadata: %w", err)
+			}
+		}
+
+		chunk.DocumentID = doc.ID
+		doc.Chunk = &chunk
 
 		results = append(results, &doc)
 	}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"rag-chat-bot/internal/model"
+	"strings"
+)
+
+// metadataFilterSQL menerjemahkan filter metadata menjadi predikat JSONB terhadap kolom
+// column (misalnya "d.metadata"). Nilai filter dikirim sebagai parameter kueri yang nomornya
+// dimulai setelah args, sehingga predikat dapat disisipkan ke kueri yang sudah memiliki parameter.
+// Jika filter kosong, predikat "TRUE" dikembalikan.
+func metadataFilterSQL(column string, filter model.MetadataFilter, args []interface{}) (string, []interface{}, error) {
+	conditions, err := filter.Conditions()
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid metadata filter: %w", err)
+	}
+	if len(conditions) == 0 {
+		return "TRUE", args, nil
+	}
+
+	param := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	jsonParam := func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("error encoding filter value: %w", err)
+		}
+		return param(string(data)) + "::jsonb", nil
+	}
+
+	predicates := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		var predicate string
+
+		switch c.Operator {
+		case model.FilterEq, model.FilterNe:
+			// Containment memakai indeks GIN pada kolom metadata. Untuk nilai array atau objek
+			// ini berarti "memuat", bukan sama persis (lihat model.MetadataFilter)
+			value, err := jsonParam(map[string]interface{}{c.Field: c.Value})
+			if err != nil {
+				return "", nil, err
+			}
+			predicate = fmt.Sprintf("%s @> %s", column, value)
+			if c.Operator == model.FilterNe {
+				predicate = "NOT (" + predicate + ")"
+			}
+
+		case model.FilterIn:
+			value, err := jsonParam(c.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			// Field skalar cocok jika sama dengan salah satu nilai, field array (misalnya tags)
+			// cocok jika memuat salah satu nilai
+			field := param(c.Field)
+			predicate = fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(%s) AS e(value) WHERE %s -> %s = e.value OR %s -> %s @> jsonb_build_array(e.value))",
+				value, column, field, column, field)
+
+		case model.FilterGt, model.FilterGte, model.FilterLt, model.FilterLte:
+			value, err := jsonParam(c.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			// Perbandingan jsonb membandingkan angka secara numerik dan string secara leksikografis
+			// (cocok untuk tanggal ISO 8601); tipe yang berbeda tidak dianggap cocok
+			field := param(c.Field)
+			predicate = fmt.Sprintf("(jsonb_typeof(%s -> %s) = jsonb_typeof(%s) AND %s -> %s %s %s)",
+				column, field, value, column, field, rangeOperators[c.Operator], value)
+
+		case model.FilterExists:
+			predicate = fmt.Sprintf("%s ? %s", column, param(c.Field))
+			if exists, _ := c.Value.(bool); !exists {
+				predicate = "NOT (" + predicate + ")"
+			}
+		}
+
+		predicates = append(predicates, predicate)
+	}
+
+	return "(" + strings.Join(predicates, " AND ") + ")", args, nil
+}
+
+// rangeOperators memetakan operator rentang filter ke operator SQL
+var rangeOperators = map[string]string{
+	model.FilterGt:  ">",
+	model.FilterGte: ">=",
+	model.FilterLt:  "<",
+	model.FilterLte: "<=",
+}
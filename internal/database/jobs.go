@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rag-chat-bot/internal/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrJobNotFound dikembalikan saat job dengan ID yang diminta tidak ada
+var ErrJobNotFound = errors.New("job not found")
+
+// jobColumns adalah kolom yang dipilih oleh kueri ingestion_jobs
+const jobColumns = `
+	id, kind, status, payload, result, error, stage, progress_done, progress_total,
+	attempts, max_attempts, run_after, created_at, updated_at, started_at, finished_at`
+
+// EnqueueJob menambahkan job baru ke antrean dan mengembalikan ID-nya
+func (db *PostgresDB) EnqueueJob(ctx context.Context, kind string, payload interface{}, maxAttempts int) (int, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("error encoding job payload: %w", err)
+	}
+
+	var jobID int
+	err = db.pool.QueryRow(ctx,
+		"INSERT INTO ingestion_jobs (kind, payload, max_attempts) VALUES ($1, $2, $3) RETURNING id",
+		kind, data, maxAttempts).Scan(&jobID)
+	if err != nil {
+		return 0, fmt.Errorf("error enqueuing job: %w", err)
+	}
+	return jobID, nil
+}
+
+// ClaimJob mengambil satu job yang siap dijalankan dan menandainya sebagai running.
+// FOR UPDATE SKIP LOCKED memastikan setiap job hanya diambil satu worker, termasuk antar
+// instance server. Job running yang tidak diperbarui selama staleAfter (misalnya karena
+// prosesnya mati) dianggap ditinggalkan dan dapat diambil kembali selama percobaannya belum
+// habis; job ditinggalkan yang percobaannya habis ditandai gagal, sehingga job yang selalu
+// mematikan worker tidak diambil terus-menerus. Jika tidak ada job, nil dikembalikan tanpa error.
+func (db *PostgresDB) ClaimJob(ctx context.Context, kinds []string, staleAfter time.Duration) (*model.Job, error) {
+	row := db.pool.QueryRow(ctx, `
+		WITH exhausted AS (
+			UPDATE ingestion_jobs
+			SET status = 'failed', error = 'job was abandoned by its worker and has no attempts left',
+			    updated_at = NOW(), finished_at = NOW()
+			WHERE kind = ANY($1) AND status = 'running' AND attempts >= max_attempts
+			  AND updated_at < NOW() - make_interval(secs => $2::float8)
+		)
+		UPDATE ingestion_jobs
+		SET status = 'running', attempts = attempts + 1, error = '',
+		    started_at = NOW(), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM ingestion_jobs
+			WHERE kind = ANY($1)
+			  AND ((status = 'pending' AND run_after <= NOW())
+			    OR (status = 'running' AND attempts < max_attempts
+			        AND updated_at < NOW() - make_interval(secs => $2::float8)))
+			ORDER BY run_after, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING `+jobColumns, kinds, staleAfter.Seconds())
+
+	job, err := scanJob(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error claiming job: %w", err)
+	}
+	return job, nil
+}
+
+// UpdateJobProgress mencatat tahap dan kemajuan job yang sedang berjalan
+func (db *PostgresDB) UpdateJobProgress(ctx context.Context, jobID int, stage string, done, total int) error {
+	_, err := db.pool.Exec(ctx, `
+		UPDATE ingestion_jobs
+		SET stage = $2, progress_done = $3, progress_total = $4, updated_at = NOW()
+		WHERE id = $1
+	`, jobID, stage, done, total)
+	if err != nil {
+		return fmt.Errorf("error updating job progress: %w", err)
+	}
+	return nil
+}
+
+// TouchJob memperbarui updated_at job yang sedang berjalan agar tidak dianggap ditinggalkan
+func (db *PostgresDB) TouchJob(ctx context.Context, jobID int) error {
+	_, err := db.pool.Exec(ctx, "UPDATE ingestion_jobs SET updated_at = NOW() WHERE id = $1 AND status = 'running'", jobID)
+	if err != nil {
+		return fmt.Errorf("error updating job: %w", err)
+	}
+	return nil
+}
+
+// CompleteJob menandai job berhasil dan menyimpan hasilnya
+func (db *PostgresDB) CompleteJob(ctx context.Context, jobID int, result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error encoding job result: %w", err)
+	}
+
+	_, err = db.pool.Exec(ctx, `
+		UPDATE ingestion_jobs
+		SET status = 'succeeded', result = $2, stage = 'done', updated_at = NOW(), finished_at = NOW()
+		WHERE id = $1
+	`, jobID, data)
+	if err != nil {
+		return fmt.Errorf("error completing job: %w", err)
+	}
+	return nil
+}
+
+// FailJob mencatat kegagalan job. Jika retryAfter lebih dari nol dan percobaan belum habis,
+// job dikembalikan ke antrean untuk dijalankan lagi setelah retryAfter; selain itu job gagal permanen.
+func (db *PostgresDB) FailJob(ctx context.Context, jobID int, jobErr error, retryAfter time.Duration) error {
+	_, err := db.pool.Exec(ctx, `
+		UPDATE ingestion_jobs
+		SET status = CASE WHEN $3::float8 > 0 AND attempts < max_attempts THEN 'pending' ELSE 'failed' END,
+		    run_after = NOW() + make_interval(secs => $3::float8),
+		    finished_at = CASE WHEN $3::float8 > 0 AND attempts < max_attempts THEN NULL ELSE NOW() END,
+		    error = $2, updated_at = NOW()
+		WHERE id = $1
+	`, jobID, jobErr.Error(), retryAfter.Seconds())
+	if err != nil {
+		return fmt.Errorf("error failing job: %w", err)
+	}
+	return nil
+}
+
+// ReleaseJob mengembalikan job yang belum selesai ke antrean tanpa menghitungnya sebagai
+// percobaan, misalnya saat server dimatikan di tengah pemrosesan
+func (db *PostgresDB) ReleaseJob(ctx context.Context, jobID int) error {
+	_, err := db.pool.Exec(ctx, `
+		UPDATE ingestion_jobs
+		SET status = 'pending', attempts = GREATEST(attempts - 1, 0), run_after = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`, jobID)
+	if err != nil {
+		return fmt.Errorf("error releasing job: %w", err)
+	}
+	return nil
+}
+
+// GetJob mengambil job berdasarkan ID
+func (db *PostgresDB) GetJob(ctx context.Context, jobID int) (*model.Job, error) {
+	job, err := scanJob(db.pool.QueryRow(ctx, "SELECT "+jobColumns+" FROM ingestion_jobs WHERE id = $1", jobID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting job: %w", err)
+	}
+	return job, nil
+}
+
+// scanJob membaca satu baris ingestion_jobs
+func scanJob(row pgx.Row) (*model.Job, error) {
+	var job model.Job
+	var result []byte
+	err := row.Scan(&job.ID, &job.Kind, &job.Status, &job.Payload, &result, &job.Error, &job.Stage,
+		&job.ProgressDone, &job.ProgressTotal, &job.Attempts, &job.MaxAttempts, &job.RunAfter,
+		&job.CreatedAt, &job.UpdatedAt, &job.StartedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		job.Result = result
+	}
+	return &job, nil
+}
@@ -0,0 +1,263 @@
+package dirsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"rag-chat-bot/internal/extract"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/rag"
+	"strings"
+	"time"
+)
+
+// MetadataSourcePath adalah kunci metadata tempat path absolut berkas sumber disimpan
+const MetadataSourcePath = "source_path"
+
+// maxFileBytes membatasi ukuran berkas yang diproses, sama dengan batas unggahan API
+const maxFileBytes = 32 << 20
+
+// Options mengatur perilaku Watcher
+type Options struct {
+	// Interval adalah jeda antarpemindaian direktori
+	Interval time.Duration
+	// Metadata ditambahkan ke setiap dokumen yang disinkronkan
+	Metadata map[string]interface{}
+}
+
+// Stats merangkum hasil satu kali sinkronisasi
+type Stats struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Deleted   int
+	Skipped   int
+	Failed    int
+}
+
+// String mengembalikan ringkasan Stats untuk log
+func (s Stats) String() string {
+	return fmt.Sprintf("%d created, %d updated, %d unchanged, %d deleted, %d skipped, %d failed",
+		s.Created, s.Updated, s.Unchanged, s.Deleted, s.Skipped, s.Failed)
+}
+
+// fileState adalah ukuran dan waktu modifikasi berkas saat terakhir berhasil disinkronkan
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Watcher menjaga isi sebuah direktori tetap sinkron dengan indeks dokumen. Direktori dipindai
+// secara berkala (polling) sehingga bekerja juga pada volume jaringan dan bind mount yang tidak
+// mengirim event filesystem. Setiap berkas disimpan sebagai satu dokumen dengan kunci metadata
+// source_path; berkas baru di-ingest, berkas yang hash kontennya berubah di-embed ulang, dan
+// dokumen untuk berkas yang dihapus ikut dihapus.
+type Watcher struct {
+	processor *rag.Processor
+	root      string
+	opts      Options
+	// known menyimpan berkas yang sudah sinkron agar berkas yang tidak berubah tidak dibaca ulang
+	known map[string]fileState
+}
+
+// New membuat Watcher baru untuk direktori root
+func New(processor *rag.Processor, root string, opts Options) (*Watcher, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving directory: %w", err)
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", absRoot)
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second // Default value
+	}
+
+	return &Watcher{
+		processor: processor,
+		root:      absRoot,
+		opts:      opts,
+		known:     make(map[string]fileState),
+	}, nil
+}
+
+// Run menyinkronkan direktori setiap Interval sampai ctx dibatalkan
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		stats, err := w.Sync(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Error syncing %s: %v", w.root, err)
+		} else if stats.Created+stats.Updated+stats.Deleted+stats.Failed > 0 {
+			log.Printf("Synced %s: %s", w.root, stats)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync memindai direktori satu kali. Dokumen untuk berkas yang dihapus, atau yang tidak dapat
+// diindeks lagi (kosong, gagal diekstrak, terlalu besar), ikut dihapus agar isi lamanya tidak
+// tetap muncul di hasil pencarian. Dokumen hanya dihapus jika seluruh direktori berhasil
+// dipindai, sehingga direktori yang sementara tidak dapat dibaca (misalnya volume yang belum
+// ter-mount) tidak mengosongkan indeks.
+func (w *Watcher) Sync(ctx context.Context) (Stats, error) {
+	var stats Stats
+
+	existing, err := w.processor.DocumentIDsByMetadata(ctx, MetadataSourcePath)
+	if err != nil {
+		return stats, err
+	}
+
+	present := make(map[string]bool)
+	walkErr := filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Lewati berkas dan direktori tersembunyi seperti .git
+		if path != w.root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if w.syncFile(ctx, path, &stats) {
+			present[path] = true
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return stats, fmt.Errorf("error scanning directory: %w", walkErr)
+	}
+
+	// Hapus dokumen untuk berkas di bawah root yang sudah tidak ada atau tidak dapat diindeks
+	prefix := w.root + string(filepath.Separator)
+	for path, docID := range existing {
+		if !strings.HasPrefix(path, prefix) || present[path] {
+			continue
+		}
+		if err := w.processor.DeleteDocument(ctx, docID); err != nil {
+			log.Printf("Error deleting document %d for removed file %s: %v", docID, path, err)
+			stats.Failed++
+			continue
+		}
+		delete(w.known, path)
+		stats.Deleted++
+	}
+
+	return stats, nil
+}
+
+// syncFile mengekstrak teks berkas dan menyimpannya melalui Processor.SyncDocument. Nilai
+// kembaliannya false jika dokumen lama berkas ini harus dihapus karena isinya tidak dapat
+// diindeks lagi; error sementara seperti gagal membaca berkas tetap mempertahankannya.
+func (w *Watcher) syncFile(ctx context.Context, path string, stats *Stats) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Printf("Error reading %s: %v", path, err)
+		stats.Failed++
+		return true
+	}
+	state := fileState{size: info.Size(), modTime: info.ModTime()}
+	if known, ok := w.known[path]; ok && known == state {
+		stats.Unchanged++
+		return true
+	}
+	if info.Size() > maxFileBytes {
+		stats.Skipped++
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Error reading %s: %v", path, err)
+		stats.Failed++
+		return true
+	}
+
+	rel, _ := filepath.Rel(w.root, path)
+	rel = filepath.ToSlash(rel)
+
+	result, err := extract.Extract(path, "", data)
+	if err != nil {
+		if !errors.Is(err, extract.ErrUnsupportedType) {
+			log.Printf("Error extracting %s: %v", path, err)
+		}
+		stats.Skipped++
+		return false
+	}
+	if strings.TrimSpace(result.Text) == "" {
+		stats.Skipped++
+		return false
+	}
+
+	metadata := make(map[string]interface{}, len(w.opts.Metadata)+4)
+	maps.Copy(metadata, w.opts.Metadata)
+	metadata["filename"] = rel
+	metadata["mime_type"] = result.MIMEType
+	metadata["size_bytes"] = info.Size()
+	if result.Format == extract.FormatMarkdown {
+		// HTML dan DOCX diubah menjadi Markdown sehingga dipecah per heading
+		metadata["format"] = extract.FormatMarkdown
+	}
+
+	title := result.Title
+	if title == "" {
+		title = rel
+	}
+
+	action, _, err := w.processor.SyncDocument(ctx, MetadataSourcePath, path, &model.Document{
+		Title:    title,
+		Content:  result.Text,
+		Metadata: metadata,
+	})
+	if errors.Is(err, rag.ErrEmptyDocument) {
+		stats.Skipped++
+		return false
+	}
+	if err != nil {
+		log.Printf("Error syncing %s: %v", path, err)
+		stats.Failed++
+		return true
+	}
+
+	switch action {
+	case rag.SyncDuplicate:
+		// Berkas dengan konten yang sama dengan dokumen lain tidak disimpan dua kali, dan dokumen
+		// lamanya dihapus. Berkas ini tidak dicatat sebagai sinkron agar disimpan jika dokumen
+		// lain tersebut dihapus; SyncDocument memeriksa duplikat sebelum embedding sehingga
+		// pemeriksaan ulang di setiap pemindaian tidak memanggil API embedding.
+		stats.Skipped++
+		return false
+	case rag.SyncCreated:
+		stats.Created++
+	case rag.SyncUpdated:
+		stats.Updated++
+	default:
+		stats.Unchanged++
+	}
+	w.known[path] = state
+	return true
+}
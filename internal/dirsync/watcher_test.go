@@ -0,0 +1,269 @@
+package dirsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"rag-chat-bot/internal/chunking"
+	"rag-chat-bot/internal/database"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/rag"
+	"rag-chat-bot/internal/tokenizer"
+	"testing"
+	"time"
+)
+
+// memoryStore adalah rag.DocumentStore di memori yang menolak konten duplikat seperti unique
+// index content_hash di PostgreSQL
+type memoryStore struct {
+	docs   map[int]*model.Document
+	nextID int
+}
+
+func (s *memoryStore) duplicate(id int, hash string) bool {
+	for _, doc := range s.docs {
+		if doc.ID != id && doc.ContentHash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *memoryStore) SaveDocuments(ctx context.Context, docs []*model.Document, chunks [][]*model.DocumentChunk) ([]int, error) {
+	ids := make([]int, len(docs))
+	for i, doc := range docs {
+		if s.duplicate(0, doc.ContentHash) {
+			return nil, database.ErrDuplicateDocument
+		}
+		s.nextID++
+		stored := *doc
+		stored.ID = s.nextID
+		s.docs[stored.ID] = &stored
+		ids[i] = stored.ID
+	}
+	return ids, nil
+}
+
+func (s *memoryStore) SaveDocumentVersion(ctx context.Context, previousID int, doc *model.Document, chunks []*model.DocumentChunk) (int, error) {
+	return 0, fmt.Errorf("not implemented")
+}
+
+func (s *memoryStore) UpdateDocument(ctx context.Context, doc *model.Document, chunks []*model.DocumentChunk) error {
+	if s.duplicate(doc.ID, doc.ContentHash) {
+		return database.ErrDuplicateDocument
+	}
+	stored := *doc
+	s.docs[doc.ID] = &stored
+	return nil
+}
+
+func (s *memoryStore) DeleteDocument(ctx context.Context, id int) error {
+	delete(s.docs, id)
+	return nil
+}
+
+func (s *memoryStore) GetDocument(ctx context.Context, id int) (*model.Document, error) {
+	doc, ok := s.docs[id]
+	if !ok {
+		return nil, database.ErrDocumentNotFound
+	}
+	copied := *doc
+	return &copied, nil
+}
+
+func (s *memoryStore) ListDocuments(ctx context.Context, opts database.DocumentListOptions) ([]*model.DocumentSummary, int, error) {
+	return nil, 0, nil
+}
+
+func (s *memoryStore) FindDocumentByContentHash(ctx context.Context, hash string) (*model.Document, error) {
+	for id, doc := range s.docs {
+		if doc.ContentHash == hash {
+			return s.GetDocument(ctx, id)
+		}
+	}
+	return nil, database.ErrDocumentNotFound
+}
+
+func (s *memoryStore) FindDocumentByMetadata(ctx context.Context, key, value string) (*model.Document, error) {
+	for id, doc := range s.docs {
+		if doc.Metadata[key] == value {
+			return s.GetDocument(ctx, id)
+		}
+	}
+	return nil, database.ErrDocumentNotFound
+}
+
+func (s *memoryStore) FindDocumentsWithoutChunks(ctx context.Context, limit int) ([]*model.Document, error) {
+	return nil, nil
+}
+
+func (s *memoryStore) DocumentIDsByMetadata(ctx context.Context, key string) (map[string]int, error) {
+	ids := make(map[string]int)
+	for id, doc := range s.docs {
+		if value, ok := doc.Metadata[key].(string); ok {
+			ids[value] = id
+		}
+	}
+	return ids, nil
+}
+
+// countingEmbedder adalah Embedder yang menghitung jumlah teks yang di-embed
+type countingEmbedder struct {
+	embedded int
+}
+
+func (e *countingEmbedder) CreateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	e.embedded++
+	return []float32{1, 0, 0}, nil
+}
+
+func (e *countingEmbedder) CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i], _ = e.CreateEmbedding(ctx, text)
+	}
+	return embeddings, nil
+}
+
+func (e *countingEmbedder) Dimension() int    { return 3 }
+func (e *countingEmbedder) ModelName() string { return "fake" }
+
+// writeFile menulis content ke root/name dan memajukan waktu modifikasinya agar perubahan
+// terdeteksi meskipun ukuran berkas sama
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	modTime := time.Now()
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherSync(t *testing.T) {
+	root := t.TempDir()
+	chunkers, err := chunking.NewSet(chunking.Options{Size: 200, Overlap: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &memoryStore{docs: map[int]*model.Document{}}
+	embedder := &countingEmbedder{}
+	w, err := New(rag.NewProcessor(store, embedder, chunkers, tokenizer.Estimator{}), root, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	steps := []struct {
+		name         string
+		change       func()
+		want         Stats
+		wantEmbedded bool
+		wantPaths    []string
+	}{
+		{
+			name: "create",
+			change: func() {
+				writeFile(t, root, "a.txt", "Isi berkas A.")
+				writeFile(t, root, "docs/b.md", "# B\n\nIsi berkas B.")
+				writeFile(t, root, ".git/config", "tersembunyi")
+			},
+			want:         Stats{Created: 2},
+			wantEmbedded: true,
+			wantPaths:    []string{"a.txt", "docs/b.md"},
+		},
+		{
+			name:      "unchanged",
+			change:    func() {},
+			want:      Stats{Unchanged: 2},
+			wantPaths: []string{"a.txt", "docs/b.md"},
+		},
+		{
+			name:         "update",
+			change:       func() { writeFile(t, root, "a.txt", "Isi berkas A yang berubah.") },
+			want:         Stats{Updated: 1, Unchanged: 1},
+			wantEmbedded: true,
+			wantPaths:    []string{"a.txt", "docs/b.md"},
+		},
+		{
+			name:      "duplicate",
+			change:    func() { writeFile(t, root, "c.txt", "Isi berkas A yang berubah.") },
+			want:      Stats{Unchanged: 2, Skipped: 1},
+			wantPaths: []string{"a.txt", "docs/b.md"},
+		},
+		{
+			name:      "duplicate is not embedded again",
+			change:    func() {},
+			want:      Stats{Unchanged: 2, Skipped: 1},
+			wantPaths: []string{"a.txt", "docs/b.md"},
+		},
+		{
+			name: "delete",
+			change: func() {
+				if err := os.Remove(filepath.Join(root, "docs", "b.md")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:      Stats{Unchanged: 1, Skipped: 1, Deleted: 1},
+			wantPaths: []string{"a.txt"},
+		},
+		{
+			name: "original deleted",
+			change: func() {
+				if err := os.Remove(filepath.Join(root, "a.txt")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:      Stats{Skipped: 1, Deleted: 1},
+			wantPaths: []string{},
+		},
+		{
+			name:         "duplicate stored after original is deleted",
+			change:       func() {},
+			want:         Stats{Created: 1},
+			wantEmbedded: true,
+			wantPaths:    []string{"c.txt"},
+		},
+		{
+			name:      "emptied file",
+			change:    func() { writeFile(t, root, "c.txt", " \n") },
+			want:      Stats{Skipped: 1, Deleted: 1},
+			wantPaths: []string{},
+		},
+	}
+
+	for _, step := range steps {
+		step.change()
+		embedder.embedded = 0
+
+		stats, err := w.Sync(ctx)
+		if err != nil {
+			t.Fatalf("%s: Sync() error = %v", step.name, err)
+		}
+		if stats != step.want {
+			t.Errorf("%s: Sync() = %+v, want %+v", step.name, stats, step.want)
+		}
+		if embedded := embedder.embedded > 0; embedded != step.wantEmbedded {
+			t.Errorf("%s: embedded = %v, want %v", step.name, embedded, step.wantEmbedded)
+		}
+
+		ids, _ := store.DocumentIDsByMetadata(ctx, MetadataSourcePath)
+		if len(ids) != len(step.wantPaths) {
+			t.Errorf("%s: %d documents, want %v", step.name, len(ids), step.wantPaths)
+		}
+		for _, rel := range step.wantPaths {
+			if _, ok := ids[filepath.Join(w.root, filepath.FromSlash(rel))]; !ok {
+				t.Errorf("%s: no document for %s", step.name, rel)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package embedding
+
+import (
+	"context"
+	"rag-chat-bot/internal/tokenizer"
+	"sync"
+)
+
+// batch adalah rentang indeks [start, end) dari teks input yang dikirim dalam satu permintaan
+type batch struct {
+	start int
+	end   int
+}
+
+// splitBatches membagi teks menjadi beberapa batch berdasarkan jumlah item dan anggaran token.
+// Teks yang melebihi anggaran token sendirian tetap dikirim sebagai satu batch.
+func splitBatches(texts []string, maxItems, maxTokens int, counter tokenizer.Counter) []batch {
+	var batches []batch
+	start, tokens := 0, 0
+
+	for i, text := range texts {
+		textTokens := counter.Count(text)
+		full := maxItems > 0 && i-start >= maxItems
+		overBudget := maxTokens > 0 && i > start && tokens+textTokens > maxTokens
+		if full || overBudget {
+			batches = append(batches, batch{start: start, end: i})
+			start, tokens = i, 0
+		}
+		tokens += textTokens
+	}
+
+	if start < len(texts) {
+		batches = append(batches, batch{start: start, end: len(texts)})
+	}
+
+	return batches
+}
+
+// runBatches menjalankan fn untuk setiap batch secara paralel dengan jumlah worker terbatas.
+// Batch yang tersisa dibatalkan saat salah satu batch gagal dan error pertama dikembalikan.
+func runBatches(ctx context.Context, batches []batch, concurrency int, fn func(ctx context.Context, b batch) error) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, b := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(b batch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, b); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(b)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
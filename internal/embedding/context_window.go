@@ -0,0 +1,57 @@
+package embedding
+
+import "strings"
+
+// defaultContextWindow dipakai untuk model yang context window-nya tidak dikenal
+const defaultContextWindow = 8192
+
+// defaultOutputReserve adalah jumlah token yang dicadangkan untuk jawaban saat batas panjang
+// jawaban tidak dikonfigurasi
+const defaultOutputReserve = 1024
+
+// outputReserve mengembalikan jumlah token yang dicadangkan untuk jawaban. maxOutputTokens
+// bernilai nol berarti jawaban tidak dibatasi, tetapi anggaran prompt tetap menyisakan ruang.
+func outputReserve(maxOutputTokens int) int {
+	if maxOutputTokens > 0 {
+		return maxOutputTokens
+	}
+	return defaultOutputReserve
+}
+
+// openAIContextWindows berisi context window model chat OpenAI yang dikenal, dicocokkan
+// berdasarkan awalan nama model terpanjang
+var openAIContextWindows = map[string]int{
+	"gpt-3.5-turbo":          16385,
+	"gpt-3.5-turbo-instruct": 4096,
+	"gpt-4":                  8192,
+	"gpt-4-32k":              32768,
+	"gpt-4-turbo":            128000,
+	"gpt-4-1106":             128000,
+	"gpt-4-0125":             128000,
+	"gpt-4o":                 128000,
+	"gpt-4.1":                1047576,
+	"o1":                     200000,
+	"o3":                     200000,
+	"o4-mini":                200000,
+}
+
+// ollamaDefaultContextWindow adalah anggaran prompt Ollama saat CHAT_CONTEXT_WINDOW tidak diisi,
+// sama dengan num_ctx default server Ollama. Context window maksimum model (hingga 128k token)
+// sengaja tidak dipakai karena Ollama mengalokasikan KV cache sebesar num_ctx.
+const ollamaDefaultContextWindow = 4096
+
+// contextWindow mengembalikan configured jika lebih dari nol, atau context window model dari
+// windows berdasarkan awalan nama terpanjang
+func contextWindow(configured int, windows map[string]int, modelName string) int {
+	if configured > 0 {
+		return configured
+	}
+
+	window, matched := defaultContextWindow, 0
+	for prefix, size := range windows {
+		if strings.HasPrefix(modelName, prefix) && len(prefix) > matched {
+			window, matched = size, len(prefix)
+		}
+	}
+	return window
+}
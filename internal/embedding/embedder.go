@@ -0,0 +1,49 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"rag-chat-bot/internal/config"
+	"rag-chat-bot/internal/tokenizer"
+)
+
+// Embedder adalah antarmuka untuk penyedia embedding teks
+type Embedder interface {
+	// CreateEmbedding membuat embedding vektor dari satu teks
+	CreateEmbedding(ctx context.Context, text string) ([]float32, error)
+
+	// CreateEmbeddings membuat embedding vektor untuk beberapa teks sekaligus,
+	// urutan hasil sama dengan urutan input
+	CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Dimension mengembalikan dimensi vektor yang dihasilkan
+	Dimension() int
+
+	// ModelName mengembalikan nama model embedding yang digunakan
+	ModelName() string
+}
+
+// NewEmbedder membuat Embedder sesuai penyedia yang dipilih di konfigurasi
+func NewEmbedder(cfg *config.Config, tokens tokenizer.Counter) (Embedder, error) {
+	switch cfg.EmbeddingProvider {
+	case "", "openai":
+		return NewOpenAIEmbedding(cfg, tokens), nil
+	case "ollama":
+		return NewOllamaEmbedding(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s", cfg.EmbeddingProvider)
+	}
+}
+
+// embeddingPricesPer1M berisi harga embedding OpenAI dalam USD per satu juta token
+var embeddingPricesPer1M = map[string]float64{
+	"text-embedding-ada-002": 0.10,
+	"text-embedding-3-small": 0.02,
+	"text-embedding-3-large": 0.13,
+}
+
+// EstimateCost memperkirakan biaya embedding dalam USD untuk sejumlah token.
+// Model yang harganya tidak diketahui (misalnya model lokal) dianggap gratis.
+func EstimateCost(model string, tokens int) float64 {
+	return embeddingPricesPer1M[model] * float64(tokens) / 1_000_000
+}
@@ -0,0 +1,115 @@
+package embedding
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError adalah error yang dikembalikan oleh API penyedia model
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+}
+
+// Error mengimplementasikan antarmuka error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// RateLimitError menandakan permintaan ditolak karena batas laju atau kuota (HTTP 429)
+type RateLimitError struct {
+	APIError
+	// RetryAfter adalah waktu tunggu yang diminta server, nol jika tidak disebutkan
+	RetryAfter time.Duration
+}
+
+// Unwrap mengembalikan APIError dasar agar dapat diperiksa dengan errors.As
+func (e *RateLimitError) Unwrap() error {
+	return &e.APIError
+}
+
+// AuthError menandakan API key tidak valid atau tidak memiliki akses (HTTP 401/403)
+type AuthError struct {
+	APIError
+}
+
+// Unwrap mengembalikan APIError dasar agar dapat diperiksa dengan errors.As
+func (e *AuthError) Unwrap() error {
+	return &e.APIError
+}
+
+// ContextLengthError menandakan input melebihi panjang konteks maksimum model
+type ContextLengthError struct {
+	APIError
+}
+
+// Unwrap mengembalikan APIError dasar agar dapat diperiksa dengan errors.As
+func (e *ContextLengthError) Unwrap() error {
+	return &e.APIError
+}
+
+// openAIErrorResponse adalah format body error dari OpenAI API
+type openAIErrorResponse struct {
+	Error struct {
+		Message string      `json:"message"`
+		Type    string      `json:"type"`
+		Code    interface{} `json:"code"`
+	} `json:"error"`
+}
+
+// parseOpenAIError mengubah respons non-200 dari OpenAI API menjadi error bertipe
+func parseOpenAIError(resp *http.Response, body []byte) error {
+	apiErr := APIError{
+		Provider:   "OpenAI",
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+	}
+
+	var errResp openAIErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+		apiErr.Message = errResp.Error.Message
+		apiErr.Type = errResp.Error.Type
+		if errResp.Error.Code != nil {
+			apiErr.Code = fmt.Sprint(errResp.Error.Code)
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitError{APIError: apiErr, RetryAfter: parseRetryAfter(resp.Header)}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &AuthError{APIError: apiErr}
+	case apiErr.Code == "context_length_exceeded" || strings.Contains(apiErr.Message, "maximum context length"):
+		return &ContextLengthError{APIError: apiErr}
+	default:
+		return &apiErr
+	}
+}
+
+// parseRetryAfter membaca header retry-after-ms atau Retry-After (detik atau tanggal HTTP)
+func parseRetryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"rag-chat-bot/internal/config"
+)
+
+// ChatModel adalah antarmuka untuk penyedia model bahasa (LLM) yang menghasilkan chat completion
+type ChatModel interface {
+	// ChatCompletion mengirim daftar pesan ke model dan mengembalikan hasil completion
+	ChatCompletion(ctx context.Context, messages []ChatCompletionMessage) (*Completion, error)
+
+	// ModelName mengembalikan nama model chat yang digunakan
+	ModelName() string
+
+	// ContextWindow mengembalikan jumlah token maksimum prompt dan jawaban dalam satu permintaan
+	ContextWindow() int
+
+	// MaxOutputTokens mengembalikan jumlah token yang dicadangkan untuk jawaban
+	MaxOutputTokens() int
+}
+
+// StreamingChatModel adalah ChatModel yang dapat mengirim potongan jawaban secara bertahap
+type StreamingChatModel interface {
+	ChatModel
+
+	// ChatCompletionStream memanggil onDelta untuk setiap potongan teks yang diterima dan
+	// mengembalikan completion lengkap. Jika streaming terhenti karena error atau context
+	// dibatalkan, completion berisi teks parsial yang sudah diterima beserta error-nya.
+	ChatCompletionStream(ctx context.Context, messages []ChatCompletionMessage, onDelta func(delta string) error) (*Completion, error)
+}
+
+// ChatCompletionMessage adalah format pesan untuk chat completion
+type ChatCompletionMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Completion adalah hasil chat completion dari model bahasa
+type Completion struct {
+	Content      string `json:"content"`
+	FinishReason string `json:"finish_reason"`
+	Usage        Usage  `json:"usage"`
+}
+
+// Usage mencatat jumlah token yang digunakan oleh sebuah permintaan
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// NewChatModel membuat ChatModel sesuai penyedia yang dipilih di konfigurasi
+func NewChatModel(cfg *config.Config) (ChatModel, error) {
+	switch cfg.ChatProvider {
+	case "", "openai":
+		return NewOpenAIChat(cfg), nil
+	case "ollama":
+		return NewOllamaChat(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported chat provider: %s", cfg.ChatProvider)
+	}
+}
@@ -0,0 +1,355 @@
+package embedding
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"rag-chat-bot/internal/config"
+	"rag-chat-bot/internal/tokenizer"
+	"strings"
+)
+
+// ollamaClient menangani komunikasi HTTP dengan server Ollama
+type ollamaClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// newOllamaClient membuat klien HTTP untuk server Ollama
+func newOllamaClient(cfg *config.Config) *ollamaClient {
+	return &ollamaClient{
+		baseURL:    strings.TrimRight(cfg.OllamaBaseURL, "/"),
+		httpClient: &http.Client{},
+	}
+}
+
+// post mengirim permintaan JSON ke endpoint Ollama dan mengurai respons ke out
+func (c *ollamaClient) post(ctx context.Context, path string, payload interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Ollama API error: %s", string(body))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	return nil
+}
+
+// stream membuka permintaan streaming ke endpoint Ollama. Pemanggil wajib menutup body respons.
+func (c *ollamaClient) stream(ctx context.Context, path string, payload interface{}) (*http.Response, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama API error: %s", string(body))
+	}
+
+	return resp, nil
+}
+
+// OllamaEmbedding adalah klien untuk membuat embedding menggunakan endpoint /api/embeddings Ollama
+type OllamaEmbedding struct {
+	client         *ollamaClient
+	embeddingModel string
+	dimension      int
+	concurrency    int
+}
+
+// OllamaEmbeddingRequest adalah struktur untuk permintaan embedding ke Ollama
+type OllamaEmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// OllamaEmbeddingResponse adalah struktur untuk respons embedding dari Ollama
+type OllamaEmbeddingResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+// ollamaModelDimensions berisi dimensi bawaan model embedding Ollama yang umum dipakai
+var ollamaModelDimensions = map[string]int{
+	"nomic-embed-text":  768,
+	"mxbai-embed-large": 1024,
+	"all-minilm":        384,
+	"bge-m3":            1024,
+}
+
+// NewOllamaEmbedding membuat klien baru untuk embedding Ollama
+func NewOllamaEmbedding(cfg *config.Config) (*OllamaEmbedding, error) {
+	dimension := cfg.EmbeddingDimension
+	if dimension <= 0 {
+		// Nama model Ollama dapat memiliki tag, misalnya "nomic-embed-text:latest"
+		dimension = ollamaModelDimensions[strings.SplitN(cfg.OllamaEmbeddingModel, ":", 2)[0]]
+	}
+	if dimension <= 0 {
+		return nil, fmt.Errorf("EMBEDDING_DIMENSION is required for Ollama model %s", cfg.OllamaEmbeddingModel)
+	}
+
+	return &OllamaEmbedding{
+		client:         newOllamaClient(cfg),
+		embeddingModel: cfg.OllamaEmbeddingModel,
+		dimension:      dimension,
+		concurrency:    cfg.EmbeddingConcurrency,
+	}, nil
+}
+
+// Dimension mengembalikan dimensi vektor yang dihasilkan model embedding
+func (o *OllamaEmbedding) Dimension() int {
+	return o.dimension
+}
+
+// ModelName mengembalikan nama model embedding yang digunakan
+func (o *OllamaEmbedding) ModelName() string {
+	return o.embeddingModel
+}
+
+// CreateEmbedding membuat embedding vektor dari teks
+func (o *OllamaEmbedding) CreateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	reqBody := OllamaEmbeddingRequest{
+		Model:  o.embeddingModel,
+		Prompt: text,
+	}
+
+	var embeddingResp OllamaEmbeddingResponse
+	if err := o.client.post(ctx, "/api/embeddings", reqBody, &embeddingResp); err != nil {
+		return nil, err
+	}
+
+	if len(embeddingResp.Embedding) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+
+	return embeddingResp.Embedding, nil
+}
+
+// CreateEmbeddings membuat embedding vektor untuk beberapa teks. Endpoint /api/embeddings
+// hanya menerima satu prompt, sehingga setiap teks dikirim terpisah dengan worker terbatas.
+func (o *OllamaEmbedding) CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	embeddings := make([][]float32, len(texts))
+	batches := splitBatches(texts, 1, 0, tokenizer.Estimator{})
+
+	err := runBatches(ctx, batches, o.concurrency, func(ctx context.Context, b batch) error {
+		embedding, err := o.CreateEmbedding(ctx, texts[b.start])
+		if err != nil {
+			return fmt.Errorf("error embedding text %d: %w", b.start, err)
+		}
+		embeddings[b.start] = embedding
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+// OllamaChat adalah klien untuk chat completion menggunakan endpoint /api/chat Ollama
+type OllamaChat struct {
+	client          *ollamaClient
+	chatModel       string
+	contextWindow   int
+	maxOutputTokens int
+}
+
+// OllamaChatRequest adalah struktur untuk permintaan chat ke Ollama
+type OllamaChatRequest struct {
+	Model    string                  `json:"model"`
+	Messages []ChatCompletionMessage `json:"messages"`
+	Stream   bool                    `json:"stream"`
+	Options  OllamaChatOptions       `json:"options"`
+}
+
+// OllamaChatOptions adalah parameter model untuk permintaan chat Ollama
+type OllamaChatOptions struct {
+	// NumCtx adalah ukuran context window; server memakai num_ctx bawaannya jika tidak diisi
+	NumCtx int `json:"num_ctx,omitempty"`
+	// NumPredict membatasi panjang jawaban
+	NumPredict int `json:"num_predict,omitempty"`
+}
+
+// OllamaChatResponse adalah struktur untuk respons chat dari Ollama
+type OllamaChatResponse struct {
+	Model   string                `json:"model"`
+	Message ChatCompletionMessage `json:"message"`
+	Done    bool                  `json:"done"`
+	// DoneReason berisi alasan selesai, misalnya "stop" atau "length"
+	DoneReason      string `json:"done_reason"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+}
+
+// NewOllamaChat membuat klien baru untuk chat Ollama
+func NewOllamaChat(cfg *config.Config) *OllamaChat {
+	return &OllamaChat{
+		client:          newOllamaClient(cfg),
+		chatModel:       cfg.OllamaChatModel,
+		contextWindow:   cfg.ChatContextWindow,
+		maxOutputTokens: cfg.ChatMaxOutputTokens,
+	}
+}
+
+// ModelName mengembalikan nama model chat yang digunakan
+func (o *OllamaChat) ModelName() string {
+	return o.chatModel
+}
+
+// ContextWindow mengembalikan jumlah token maksimum prompt dan jawaban dalam satu permintaan
+func (o *OllamaChat) ContextWindow() int {
+	if o.contextWindow > 0 {
+		return o.contextWindow
+	}
+	return ollamaDefaultContextWindow
+}
+
+// MaxOutputTokens mengembalikan jumlah token yang dicadangkan untuk jawaban
+func (o *OllamaChat) MaxOutputTokens() int {
+	return outputReserve(o.maxOutputTokens)
+}
+
+// options mengembalikan parameter model untuk setiap permintaan chat. num_ctx dan num_predict
+// hanya dikirim jika CHAT_CONTEXT_WINDOW dan CHAT_MAX_OUTPUT_TOKENS diisi, sehingga pengaturan
+// server Ollama tetap berlaku.
+func (o *OllamaChat) options() OllamaChatOptions {
+	return OllamaChatOptions{NumCtx: o.contextWindow, NumPredict: o.maxOutputTokens}
+}
+
+// ChatCompletion membuat chat completion dengan Ollama
+func (o *OllamaChat) ChatCompletion(ctx context.Context, messages []ChatCompletionMessage) (*Completion, error) {
+	reqBody := OllamaChatRequest{
+		Model:    o.chatModel,
+		Messages: messages,
+		Stream:   false,
+		Options:  o.options(),
+	}
+
+	var chatResp OllamaChatResponse
+	if err := o.client.post(ctx, "/api/chat", reqBody, &chatResp); err != nil {
+		return nil, err
+	}
+
+	finishReason := chatResp.DoneReason
+	if finishReason == "" && chatResp.Done {
+		finishReason = "stop"
+	}
+
+	return &Completion{
+		Content:      chatResp.Message.Content,
+		FinishReason: finishReason,
+		Usage: Usage{
+			PromptTokens:     chatResp.PromptEvalCount,
+			CompletionTokens: chatResp.EvalCount,
+			TotalTokens:      chatResp.PromptEvalCount + chatResp.EvalCount,
+		},
+	}, nil
+}
+
+// ChatCompletionStream membuat chat completion dengan Ollama menggunakan respons NDJSON bertahap
+func (o *OllamaChat) ChatCompletionStream(ctx context.Context, messages []ChatCompletionMessage, onDelta func(delta string) error) (*Completion, error) {
+	reqBody := OllamaChatRequest{
+		Model:    o.chatModel,
+		Messages: messages,
+		Stream:   true,
+		Options:  o.options(),
+	}
+
+	resp, err := o.client.stream(ctx, "/api/chat", reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	completion := &Completion{}
+	var content strings.Builder
+
+	// Setiap baris adalah satu objek JSON OllamaChatResponse
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var chunk OllamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			completion.Content = content.String()
+			return completion, fmt.Errorf("error unmarshaling stream chunk: %w", err)
+		}
+
+		if chunk.Message.Content != "" {
+			content.WriteString(chunk.Message.Content)
+			if err := onDelta(chunk.Message.Content); err != nil {
+				completion.Content = content.String()
+				return completion, err
+			}
+		}
+
+		if chunk.Done {
+			completion.FinishReason = chunk.DoneReason
+			if completion.FinishReason == "" {
+				completion.FinishReason = "stop"
+			}
+			completion.Usage = Usage{
+				PromptTokens:     chunk.PromptEvalCount,
+				CompletionTokens: chunk.EvalCount,
+				TotalTokens:      chunk.PromptEvalCount + chunk.EvalCount,
+			}
+			break
+		}
+	}
+
+	completion.Content = content.String()
+
+	if err := scanner.Err(); err != nil {
+		return completion, fmt.Errorf("error reading stream: %w", err)
+	}
+
+	return completion, nil
+}
@@ -0,0 +1,240 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"rag-chat-bot/internal/config"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ollamaServer menjalankan server Ollama palsu yang memeriksa path permintaan lalu membalas
+// dengan status dan body yang diberikan. Body permintaan terakhir disimpan di request.
+func ollamaServer(t *testing.T, path string, status int, body string, request *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != path {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if request != nil {
+			if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+				t.Errorf("error decoding request: %v", err)
+			}
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOllamaEmbedding(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []float32
+		wantErr string
+	}{
+		{"success", http.StatusOK, `{"embedding": [0.1, 0.2, 0.3]}`, []float32{0.1, 0.2, 0.3}, ""},
+		{"non-200", http.StatusNotFound, `{"error": "model not found"}`, nil, "model not found"},
+		{"malformed body", http.StatusOK, `{"embedding": [0.1,`, nil, "error unmarshaling response"},
+		{"empty embedding", http.StatusOK, `{"embedding": []}`, nil, "no embedding returned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request map[string]interface{}
+			srv := ollamaServer(t, "/api/embeddings", tt.status, tt.body, &request)
+			emb, err := NewOllamaEmbedding(&config.Config{
+				OllamaBaseURL:        srv.URL + "/",
+				OllamaEmbeddingModel: "nomic-embed-text:latest",
+				EmbeddingConcurrency: 1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := emb.CreateEmbedding(context.Background(), "halo")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreateEmbedding() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateEmbedding() = %v, want %v", got, tt.want)
+			}
+			if request["model"] != "nomic-embed-text:latest" || request["prompt"] != "halo" {
+				t.Errorf("unexpected request body %v", request)
+			}
+		})
+	}
+}
+
+func TestOllamaEmbeddingsKeepOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OllamaEmbeddingRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"embedding": [%d]}`, len(req.Prompt))
+	}))
+	defer srv.Close()
+
+	emb, err := NewOllamaEmbedding(&config.Config{
+		OllamaBaseURL:        srv.URL,
+		OllamaEmbeddingModel: "all-minilm",
+		EmbeddingConcurrency: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emb.Dimension() != 384 {
+		t.Errorf("Dimension() = %d, want 384", emb.Dimension())
+	}
+
+	texts := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	got, err := emb.CreateEmbeddings(context.Background(), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, vec := range got {
+		if len(vec) != 1 || int(vec[0]) != len(texts[i]) {
+			t.Errorf("embedding %d = %v, want [%d]", i, vec, len(texts[i]))
+		}
+	}
+}
+
+func TestOllamaChat(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *Completion
+		wantErr string
+	}{
+		{
+			"success", http.StatusOK,
+			`{"model": "llama3", "message": {"role": "assistant", "content": "Halo!"}, "done": true, "done_reason": "length", "prompt_eval_count": 12, "eval_count": 3}`,
+			&Completion{Content: "Halo!", FinishReason: "length", Usage: Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}},
+			"",
+		},
+		{
+			"done without reason", http.StatusOK,
+			`{"message": {"role": "assistant", "content": "Ok"}, "done": true}`,
+			&Completion{Content: "Ok", FinishReason: "stop"},
+			"",
+		},
+		{"non-200", http.StatusInternalServerError, `{"error": "out of memory"}`, nil, "out of memory"},
+		{"malformed body", http.StatusOK, `not json`, nil, "error unmarshaling response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request map[string]interface{}
+			srv := ollamaServer(t, "/api/chat", tt.status, tt.body, &request)
+			chat := NewOllamaChat(&config.Config{OllamaBaseURL: srv.URL, OllamaChatModel: "llama3"})
+
+			got, err := chat.ChatCompletion(context.Background(), []ChatCompletionMessage{{Role: "user", Content: "Halo"}})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ChatCompletion() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatCompletion() = %+v, want %+v", got, tt.want)
+			}
+			if request["model"] != "llama3" || request["stream"] != false {
+				t.Errorf("unexpected request body %v", request)
+			}
+			// num_ctx dan num_predict tidak dikirim tanpa konfigurasi
+			if options, _ := request["options"].(map[string]interface{}); len(options) != 0 {
+				t.Errorf("unexpected options %v", options)
+			}
+		})
+	}
+}
+
+func TestOllamaChatOptions(t *testing.T) {
+	var request map[string]interface{}
+	srv := ollamaServer(t, "/api/chat", http.StatusOK, `{"message": {"content": "Ok"}, "done": true}`, &request)
+	chat := NewOllamaChat(&config.Config{
+		OllamaBaseURL:       srv.URL,
+		OllamaChatModel:     "llama3",
+		ChatContextWindow:   8192,
+		ChatMaxOutputTokens: 512,
+	})
+
+	if _, err := chat.ChatCompletion(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"num_ctx": float64(8192), "num_predict": float64(512)}
+	if !reflect.DeepEqual(request["options"], want) {
+		t.Errorf("options = %v, want %v", request["options"], want)
+	}
+}
+
+func TestOllamaChatStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		deltas  []string
+		want    *Completion
+		wantErr string
+	}{
+		{
+			"success", http.StatusOK,
+			`{"message": {"content": "Ha"}, "done": false}` + "\n\n" +
+				`{"message": {"content": "lo"}, "done": false}` + "\n" +
+				`{"message": {"content": ""}, "done": true, "prompt_eval_count": 5, "eval_count": 2}` + "\n",
+			[]string{"Ha", "lo"},
+			&Completion{Content: "Halo", FinishReason: "stop", Usage: Usage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7}},
+			"",
+		},
+		{"non-200", http.StatusBadRequest, `{"error": "invalid model"}`, nil, nil, "invalid model"},
+		{
+			"malformed chunk", http.StatusOK,
+			`{"message": {"content": "Ha"}, "done": false}` + "\n" + `{"message":` + "\n",
+			[]string{"Ha"},
+			&Completion{Content: "Ha"},
+			"error unmarshaling stream chunk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := ollamaServer(t, "/api/chat", tt.status, tt.body, nil)
+			chat := NewOllamaChat(&config.Config{OllamaBaseURL: srv.URL, OllamaChatModel: "llama3"})
+
+			var deltas []string
+			got, err := chat.ChatCompletionStream(context.Background(), []ChatCompletionMessage{{Role: "user", Content: "Halo"}}, func(delta string) error {
+				deltas = append(deltas, delta)
+				return nil
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ChatCompletionStream() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(deltas, tt.deltas) {
+				t.Errorf("deltas = %q, want %q", deltas, tt.deltas)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatCompletionStream() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,180 +5,320 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"net/url"
 	"rag-chat-bot/internal/config"
+	"rag-chat-bot/internal/tokenizer"
+	"strings"
 )
 
-// OpenAIEmbedding adalah klien untuk membuat embedding menggunakan OpenAI API
-type OpenAIEmbedding struct {
-	apiKey         string
-	embeddingModel string
-	chatModel      string
+// openAIClient menangani komunikasi HTTP dengan OpenAI API maupun server yang kompatibel
+// (vLLM, LocalAI, LiteLLM) dan Azure OpenAI
+type openAIClient struct {
+	apiKey       string
+	baseURL      string
+	organization string
+	project      string
+	apiType      string
+	apiVersion   string
+	retryPolicy  RetryPolicy
+	httpClient   *http.Client
 }
 
-// EmbeddingRequest adalah struktur untuk permintaan embedding ke OpenAI API
-type EmbeddingRequest struct {
-	Model string   `json:"model"`
-	Input []string `json:"input"`
+// newOpenAIClient membuat klien HTTP untuk OpenAI API
+func newOpenAIClient(cfg *config.Config) *openAIClient {
+	return &openAIClient{
+		apiKey:       cfg.OpenAIAPIKey,
+		baseURL:      strings.TrimRight(cfg.OpenAIBaseURL, "/"),
+		organization: cfg.OpenAIOrganization,
+		project:      cfg.OpenAIProject,
+		apiType:      cfg.OpenAIAPIType,
+		apiVersion:   cfg.AzureOpenAIAPIVersion,
+		retryPolicy: RetryPolicy{
+			MaxAttempts: cfg.OpenAIRetryMaxAttempts,
+			BaseDelay:   cfg.OpenAIRetryBaseDelay,
+			MaxDelay:    cfg.OpenAIRetryMaxDelay,
+		},
+		httpClient: &http.Client{},
+	}
 }
 
-// EmbeddingResponse adalah struktur untuk respons embedding dari OpenAI API
-type EmbeddingResponse struct {
-	Object string `json:"object"`
-	Data   []struct {
-		Object    string    `json:"object"`
-		Embedding []float32 `json:"embedding"`
-		Index     int       `json:"index"`
-	} `json:"data"`
-	Model string `json:"model"`
-	Usage struct {
-		PromptTokens int `json:"prompt_tokens"`
-		TotalTokens  int `json:"total_tokens"`
-	} `json:"usage"`
+// isAzure menandakan apakah klien berbicara dengan Azure OpenAI
+func (c *openAIClient) isAzure() bool {
+	return c.apiType == "azure"
 }
 
-// NewOpenAIEmbedding membuat klien baru untuk OpenAI embedding
-func NewOpenAIEmbedding(cfg *config.Config) *OpenAIEmbedding {
-	return &OpenAIEmbedding{
-		apiKey:         cfg.OpenAIAPIKey,
-		embeddingModel: cfg.OpenAIEmbeddingModel,
-		chatModel:      cfg.OpenAIChatModel,
+// endpoint membangun URL untuk path API tertentu. Pada Azure OpenAI, URL memakai nama
+// deployment dan parameter api-version alih-alih nama model di body permintaan.
+func (c *openAIClient) endpoint(path, deployment string) string {
+	if c.isAzure() {
+		return fmt.Sprintf("%s/openai/deployments/%s%s?api-version=%s",
+			c.baseURL, url.PathEscape(deployment), path, url.QueryEscape(c.apiVersion))
 	}
+	return c.baseURL + path
 }
 
-// CreateEmbedding membuat embedding vektor dari teks
-func (o *OpenAIEmbedding) CreateEmbedding(ctx context.Context, text string) ([]float32, error) {
-	// Siapkan permintaan
-	reqBody := EmbeddingRequest{
-		Model: o.embeddingModel,
-		Input: []string{text},
+// setHeaders menambahkan header autentikasi sesuai jenis API
+func (c *openAIClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.isAzure() {
+		req.Header.Set("api-key", c.apiKey)
+		return
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.organization != "" {
+		req.Header.Set("OpenAI-Organization", c.organization)
+	}
+	if c.project != "" {
+		req.Header.Set("OpenAI-Project", c.project)
+	}
+}
+
+// post mengirim permintaan JSON ke OpenAI API dan mengurai respons ke out.
+// Permintaan dicoba ulang sesuai kebijakan retry untuk error sementara (429, 5xx, jaringan).
+func (c *openAIClient) post(ctx context.Context, path, deployment string, payload interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	var body []byte
+	err = c.retryPolicy.do(ctx, func() error {
+		body, err = c.send(ctx, path, deployment, jsonData)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Parse respons
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	return nil
+}
+
+// stream membuka permintaan streaming ke OpenAI API. Hanya pembukaan koneksi yang dicoba ulang;
+// pemanggil wajib menutup body respons.
+func (c *openAIClient) stream(ctx context.Context, path, deployment string, payload interface{}) (*http.Response, error) {
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("error marshaling request: %w", err)
 	}
 
+	var resp *http.Response
+	err = c.retryPolicy.do(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(path, deployment), bytes.NewReader(jsonData))
+		if err != nil {
+			return fmt.Errorf("error creating request: %w", err)
+		}
+
+		c.setHeaders(req)
+		req.Header.Set("Accept", "text/event-stream")
+
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("error sending request: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return fmt.Errorf("error reading response: %w", err)
+			}
+			return parseOpenAIError(resp, body)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// send mengirim satu permintaan ke OpenAI API dan mengembalikan body respons yang sukses
+func (c *openAIClient) send(ctx context.Context, path, deployment string, jsonData []byte) ([]byte, error) {
 	// Kirim permintaan ke OpenAI API
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/embeddings", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(path, deployment), bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	c.setHeaders(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Baca respons
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error reading response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error: %s", string(body))
+		return nil, parseOpenAIError(resp, body)
 	}
 
-	// Parse respons
-	var embeddingResp EmbeddingResponse
-	if err := json.Unmarshal(body, &embeddingResp); err != nil {
-		return nil, fmt.Errorf("error unmarshaling response: %w", err)
-	}
-
-	if len(embeddingResp.Data) == 0 {
-		return nil, fmt.Errorf("no embedding returned")
-	}
-
-	return embeddingResp.Data[0].Embedding, nil
+	return body, nil
 }
 
-// ChatCompletionRequest adalah struktur untuk permintaan chat completion ke OpenAI API
-type ChatCompletionRequest struct {
-	Model    string                  `json:"model"`
-	Messages []ChatCompletionMessage `json:"messages"`
+// OpenAIEmbedding adalah klien untuk membuat embedding menggunakan OpenAI API
+type OpenAIEmbedding struct {
+	client         *openAIClient
+	embeddingModel string
+	deployment     string
+	dimension      int
+	batchSize      int
+	batchMaxTokens int
+	concurrency    int
+	tokens         tokenizer.Counter
 }
 
-// ChatCompletionMessage adalah format pesan untuk chat completion
-type ChatCompletionMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+// EmbeddingRequest adalah struktur untuk permintaan embedding ke OpenAI API
+type EmbeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
 }
 
-// ChatCompletionResponse adalah struktur untuk respons chat completion dari OpenAI API
-type ChatCompletionResponse struct {
-	ID      string `json:"id"`
-	Object  string `json:"object"`
-	Created int64  `json:"created"`
-	Choices []struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
+// EmbeddingResponse adalah struktur untuk respons embedding dari OpenAI API
+type EmbeddingResponse struct {
+	Object string `json:"object"`
+	Data   []struct {
+		Object    string    `json:"object"`
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+	Model string `json:"model"`
 	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
 	} `json:"usage"`
 }
 
-// ChatCompletion membuat chat completion dengan OpenAI API
-func (o *OpenAIEmbedding) ChatCompletion(ctx context.Context, messages []ChatCompletionMessage) (string, error) {
-	// Siapkan permintaan
-	reqBody := ChatCompletionRequest{
-		Model:    o.chatModel,
-		Messages: messages,
+// openAIModelDimensions berisi dimensi bawaan model embedding OpenAI yang dikenal
+var openAIModelDimensions = map[string]int{
+	"text-embedding-ada-002": 1536,
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+}
+
+// NewOpenAIEmbedding membuat klien baru untuk OpenAI embedding. Tokenizer digunakan untuk
+// membatasi jumlah token per permintaan batch.
+func NewOpenAIEmbedding(cfg *config.Config, tokens tokenizer.Counter) *OpenAIEmbedding {
+	dimension := cfg.EmbeddingDimension
+	if dimension <= 0 {
+		dimension = openAIModelDimensions[cfg.OpenAIEmbeddingModel]
+	}
+	if dimension <= 0 {
+		dimension = 1536 // Default value
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("error marshaling request: %w", err)
+	return &OpenAIEmbedding{
+		client:         newOpenAIClient(cfg),
+		embeddingModel: cfg.OpenAIEmbeddingModel,
+		deployment:     cfg.AzureOpenAIEmbeddingDeployment,
+		dimension:      dimension,
+		batchSize:      cfg.EmbeddingBatchSize,
+		batchMaxTokens: cfg.EmbeddingBatchMaxTokens,
+		concurrency:    cfg.EmbeddingConcurrency,
+		tokens:         tokens,
 	}
+}
 
-	// Kirim permintaan ke OpenAI API
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+// Dimension mengembalikan dimensi vektor yang dihasilkan model embedding
+func (o *OpenAIEmbedding) Dimension() int {
+	return o.dimension
+}
+
+// ModelName mengembalikan nama model embedding yang digunakan
+func (o *OpenAIEmbedding) ModelName() string {
+	return o.embeddingModel
+}
+
+// CreateEmbedding membuat embedding vektor dari teks
+func (o *OpenAIEmbedding) CreateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := o.CreateEmbeddings(ctx, []string{text})
 	if err != nil {
-		return "", fmt.Errorf("error creating request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	return embeddings[0], nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error sending request: %w", err)
+// CreateEmbeddings membuat embedding vektor untuk beberapa teks. Input dibagi menjadi beberapa
+// permintaan berdasarkan jumlah item dan anggaran token, lalu dikirim paralel dengan worker terbatas.
+func (o *OpenAIEmbedding) CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
 	}
-	defer resp.Body.Close()
 
-	// Baca respons
-	body, err := ioutil.ReadAll(resp.Body)
+	embeddings := make([][]float32, len(texts))
+	batches := splitBatches(texts, o.batchSize, o.batchMaxTokens, o.tokens)
+
+	err := runBatches(ctx, batches, o.concurrency, func(ctx context.Context, b batch) error {
+		batchEmbeddings, err := o.createEmbeddingBatch(ctx, texts[b.start:b.end])
+		if err != nil {
+			return err
+		}
+		copy(embeddings[b.start:b.end], batchEmbeddings)
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("error reading response: %w", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("OpenAI API error: %s", string(body))
+	return embeddings, nil
+}
+
+// createEmbeddingBatch mengirim satu permintaan embedding untuk sekumpulan teks
+func (o *OpenAIEmbedding) createEmbeddingBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	// Siapkan permintaan
+	reqBody := EmbeddingRequest{
+		Model: o.embeddingModel,
+		Input: texts,
 	}
 
-	// Parse respons
-	var chatResp ChatCompletionResponse
-	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return "", fmt.Errorf("error unmarshaling response: %w", err)
+	// Hanya model generasi ketiga yang mendukung pengaturan dimensi
+	if strings.HasPrefix(o.embeddingModel, "text-embedding-3") && o.dimension != openAIModelDimensions[o.embeddingModel] {
+		reqBody.Dimensions = o.dimension
+	}
+
+	var embeddingResp EmbeddingResponse
+	if err := o.client.post(ctx, "/embeddings", o.deployment, reqBody, &embeddingResp); err != nil {
+		return nil, err
+	}
+
+	if len(embeddingResp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddingResp.Data))
+	}
+
+	// Tempatkan hasil berdasarkan index agar sesuai dengan urutan input
+	embeddings := make([][]float32, len(texts))
+	for _, data := range embeddingResp.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", data.Index)
+		}
+		embeddings[data.Index] = data.Embedding
 	}
 
-	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no completion returned")
+	for i, embedding := range embeddings {
+		if embedding == nil {
+			return nil, fmt.Errorf("missing embedding for input %d", i)
+		}
 	}
 
-	return chatResp.Choices[0].Message.Content, nil
+	return embeddings, nil
 }
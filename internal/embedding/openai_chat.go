@@ -0,0 +1,221 @@
+package embedding
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"rag-chat-bot/internal/config"
+	"strings"
+)
+
+// OpenAIChat adalah klien untuk chat completion menggunakan OpenAI API
+type OpenAIChat struct {
+	client          *openAIClient
+	chatModel       string
+	deployment      string
+	contextWindow   int
+	maxOutputTokens int
+}
+
+// ChatCompletionRequest adalah struktur untuk permintaan chat completion ke OpenAI API
+type ChatCompletionRequest struct {
+	Model    string                  `json:"model"`
+	Messages []ChatCompletionMessage `json:"messages"`
+	Stream   bool                    `json:"stream,omitempty"`
+	// StreamOptions meminta jumlah token di event terakhir respons streaming
+	StreamOptions *ChatStreamOptions `json:"stream_options,omitempty"`
+	// MaxTokens membatasi panjang jawaban. Model reasoning (o1, o3, o4-mini) menolak parameter
+	// ini dan memakai MaxCompletionTokens.
+	MaxTokens           int `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int `json:"max_completion_tokens,omitempty"`
+}
+
+// ChatStreamOptions adalah opsi respons streaming chat completion
+type ChatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// azureStreamUsageVersion adalah api-version Azure OpenAI pertama yang menerima stream_options
+const azureStreamUsageVersion = "2024-09-01"
+
+// reasoningModelPrefixes adalah awalan nama model OpenAI yang hanya menerima max_completion_tokens
+var reasoningModelPrefixes = []string{"o1", "o3", "o4", "gpt-5"}
+
+// ChatCompletionResponse adalah struktur untuk respons chat completion dari OpenAI API
+type ChatCompletionResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	Choices []struct {
+		Index   int `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// ChatCompletionChunk adalah struktur untuk satu event pada respons streaming chat completion
+type ChatCompletionChunk struct {
+	ID      string `json:"id"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// NewOpenAIChat membuat klien baru untuk OpenAI chat completion
+func NewOpenAIChat(cfg *config.Config) *OpenAIChat {
+	return &OpenAIChat{
+		client:          newOpenAIClient(cfg),
+		chatModel:       cfg.OpenAIChatModel,
+		deployment:      cfg.AzureOpenAIChatDeployment,
+		contextWindow:   contextWindow(cfg.ChatContextWindow, openAIContextWindows, cfg.OpenAIChatModel),
+		maxOutputTokens: cfg.ChatMaxOutputTokens,
+	}
+}
+
+// ModelName mengembalikan nama model chat yang digunakan
+func (o *OpenAIChat) ModelName() string {
+	return o.chatModel
+}
+
+// ContextWindow mengembalikan jumlah token maksimum prompt dan jawaban dalam satu permintaan
+func (o *OpenAIChat) ContextWindow() int {
+	return o.contextWindow
+}
+
+// MaxOutputTokens mengembalikan jumlah token yang dicadangkan untuk jawaban
+func (o *OpenAIChat) MaxOutputTokens() int {
+	return outputReserve(o.maxOutputTokens)
+}
+
+// newRequest membuat permintaan chat completion. Permintaan streaming meminta jumlah token
+// jika didukung API. Batas panjang jawaban hanya dikirim jika CHAT_MAX_OUTPUT_TOKENS diisi,
+// dengan parameter yang diterima model.
+func (o *OpenAIChat) newRequest(messages []ChatCompletionMessage, stream bool) ChatCompletionRequest {
+	req := ChatCompletionRequest{
+		Model:    o.chatModel,
+		Messages: messages,
+		Stream:   stream,
+	}
+	if stream && o.streamUsageSupported() {
+		req.StreamOptions = &ChatStreamOptions{IncludeUsage: true}
+	}
+	if o.maxOutputTokens <= 0 {
+		return req
+	}
+
+	for _, prefix := range reasoningModelPrefixes {
+		if strings.HasPrefix(o.chatModel, prefix) {
+			req.MaxCompletionTokens = o.maxOutputTokens
+			return req
+		}
+	}
+	req.MaxTokens = o.maxOutputTokens
+	return req
+}
+
+// streamUsageSupported menandakan apakah API menerima stream_options. Azure OpenAI menolak
+// parameter ini pada api-version sebelum azureStreamUsageVersion.
+func (o *OpenAIChat) streamUsageSupported() bool {
+	if !o.client.isAzure() {
+		return true
+	}
+	// api-version berformat YYYY-MM-DD dengan akhiran opsional seperti "-preview"
+	return o.client.apiVersion >= azureStreamUsageVersion
+}
+
+// ChatCompletion membuat chat completion dengan OpenAI API
+func (o *OpenAIChat) ChatCompletion(ctx context.Context, messages []ChatCompletionMessage) (*Completion, error) {
+	// Siapkan permintaan
+	reqBody := o.newRequest(messages, false)
+
+	var chatResp ChatCompletionResponse
+	if err := o.client.post(ctx, "/chat/completions", o.deployment, reqBody, &chatResp); err != nil {
+		return nil, err
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("no completion returned")
+	}
+
+	return &Completion{
+		Content:      chatResp.Choices[0].Message.Content,
+		FinishReason: chatResp.Choices[0].FinishReason,
+		Usage:        chatResp.Usage,
+	}, nil
+}
+
+// ChatCompletionStream membuat chat completion dengan OpenAI API menggunakan Server-Sent Events.
+// Jumlah token dibaca dari event terakhir yang tidak memiliki choices.
+func (o *OpenAIChat) ChatCompletionStream(ctx context.Context, messages []ChatCompletionMessage, onDelta func(delta string) error) (*Completion, error) {
+	reqBody := o.newRequest(messages, true)
+
+	resp, err := o.client.stream(ctx, "/chat/completions", o.deployment, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	completion := &Completion{}
+	var content strings.Builder
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			completion.Content = content.String()
+			return completion, fmt.Errorf("error unmarshaling stream chunk: %w", err)
+		}
+
+		if chunk.Usage != nil {
+			completion.Usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			completion.FinishReason = choice.FinishReason
+		}
+		if choice.Delta.Content == "" {
+			continue
+		}
+
+		content.WriteString(choice.Delta.Content)
+		if err := onDelta(choice.Delta.Content); err != nil {
+			completion.Content = content.String()
+			return completion, err
+		}
+	}
+
+	completion.Content = content.String()
+
+	if err := scanner.Err(); err != nil {
+		return completion, fmt.Errorf("error reading stream: %w", err)
+	}
+
+	return completion, nil
+}
@@ -0,0 +1,70 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"rag-chat-bot/internal/config"
+	"reflect"
+	"testing"
+)
+
+func TestOpenAIChatNewRequestStreamOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiType    string
+		apiVersion string
+		stream     bool
+		want       *ChatStreamOptions
+	}{
+		{"openai stream", "openai", "", true, &ChatStreamOptions{IncludeUsage: true}},
+		{"openai no stream", "openai", "", false, nil},
+		{"azure default version", "azure", "2024-02-01", true, nil},
+		{"azure preview version", "azure", "2024-09-01-preview", true, &ChatStreamOptions{IncludeUsage: true}},
+		{"azure ga version", "azure", "2024-10-21", true, &ChatStreamOptions{IncludeUsage: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat := NewOpenAIChat(&config.Config{
+				OpenAIAPIType:         tt.apiType,
+				AzureOpenAIAPIVersion: tt.apiVersion,
+				OpenAIChatModel:       "gpt-4o",
+			})
+			req := chat.newRequest([]ChatCompletionMessage{{Role: "user", Content: "Halo"}}, tt.stream)
+			if !reflect.DeepEqual(req.StreamOptions, tt.want) {
+				t.Errorf("StreamOptions = %+v, want %+v", req.StreamOptions, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIChatStreamUsage(t *testing.T) {
+	var got ChatCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`data: {"choices": [{"index": 0, "delta": {"content": "Ha"}}]}` + "\n\n" +
+			`data: {"choices": [{"index": 0, "delta": {"content": "lo"}, "finish_reason": "stop"}]}` + "\n\n" +
+			`data: {"choices": [], "usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}}` + "\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	chat := &OpenAIChat{client: testOpenAIClient(srv.URL), chatModel: "gpt-4o"}
+	completion, err := chat.ChatCompletionStream(context.Background(), []ChatCompletionMessage{{Role: "user", Content: "Halo"}}, func(string) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Stream || got.StreamOptions == nil || !got.StreamOptions.IncludeUsage {
+		t.Errorf("request did not ask for usage: stream=%v stream_options=%+v", got.Stream, got.StreamOptions)
+	}
+	want := &Completion{Content: "Halo", FinishReason: "stop", Usage: Usage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7}}
+	if !reflect.DeepEqual(completion, want) {
+		t.Errorf("ChatCompletionStream() = %+v, want %+v", completion, want)
+	}
+}
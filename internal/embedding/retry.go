@@ -0,0 +1,93 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy mengatur percobaan ulang untuk permintaan ke API penyedia model
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// backoff menghitung waktu tunggu eksponensial dengan jitter untuk percobaan ke-n (mulai dari 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Full jitter agar klien yang gagal bersamaan tidak mencoba ulang serentak
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// IsRetryable menentukan apakah error dari penyedia model layak dicoba ulang
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		// Kuota habis tidak akan pulih dengan mencoba ulang
+		return rateLimitErr.Code != "insufficient_quota"
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError ||
+			apiErr.StatusCode == http.StatusRequestTimeout ||
+			apiErr.StatusCode == http.StatusConflict
+	}
+
+	// Error jaringan (koneksi terputus, timeout) dianggap sementara
+	return true
+}
+
+// do menjalankan fn sesuai kebijakan percobaan ulang dan berhenti saat context dibatalkan
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt == maxAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		wait := p.backoff(attempt)
+		var rateLimitErr *RateLimitError
+		if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > wait {
+			wait = rateLimitErr.RetryAfter
+		}
+
+		log.Printf("Request failed (attempt %d/%d), retrying in %s: %v", attempt, maxAttempts, wait, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,191 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"rag-chat-bot/internal/config"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt <= 6; attempt++ {
+		limit := min(policy.BaseDelay<<(attempt-1), policy.MaxDelay)
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 200; i++ {
+			d := policy.backoff(attempt)
+			if d <= 0 || d > limit {
+				t.Fatalf("backoff(%d) = %s, want in (0, %s]", attempt, d, limit)
+			}
+			seen[d] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("backoff(%d) is not jittered", attempt)
+		}
+	}
+
+	if d := (RetryPolicy{}).backoff(1); d != 0 {
+		t.Errorf("backoff without base delay = %s, want 0", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		min, max time.Duration
+	}{
+		{"none", http.Header{}, 0, 0},
+		{"seconds", http.Header{"Retry-After": {"2"}}, 2 * time.Second, 2 * time.Second},
+		{"fractional seconds", http.Header{"Retry-After": {"1.5"}}, 1500 * time.Millisecond, 1500 * time.Millisecond},
+		{"milliseconds win", http.Header{"Retry-After": {"2"}, "Retry-After-Ms": {"250"}}, 250 * time.Millisecond, 250 * time.Millisecond},
+		{"http date", http.Header{"Retry-After": {time.Now().Add(3 * time.Second).UTC().Format(http.TimeFormat)}}, time.Second, 3 * time.Second},
+		{"past http date", http.Header{"Retry-After": {time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)}}, 0, 0},
+		{"invalid", http.Header{"Retry-After": {"soon"}}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.header); got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter() = %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+// response adalah satu balasan server palsu
+type response struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// sequenceServer membalas setiap permintaan dengan responses secara berurutan; balasan terakhir
+// diulang. Jumlah permintaan dicatat di calls.
+func sequenceServer(t *testing.T, calls *int32, responses ...response) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(calls, 1))
+		resp := responses[min(n, len(responses))-1]
+		for key, values := range resp.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(resp.status)
+		fmt.Fprint(w, resp.body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testOpenAIClient(baseURL string) *openAIClient {
+	return newOpenAIClient(&config.Config{
+		OpenAIAPIKey:           "test",
+		OpenAIBaseURL:          baseURL,
+		OpenAIAPIType:          "openai",
+		OpenAIRetryMaxAttempts: 3,
+		OpenAIRetryBaseDelay:   time.Millisecond,
+		OpenAIRetryMaxDelay:    5 * time.Millisecond,
+	})
+}
+
+func TestOpenAIClientRetry(t *testing.T) {
+	ok := response{status: http.StatusOK, body: `{"ok": true}`}
+	rateLimited := response{
+		status: http.StatusTooManyRequests,
+		header: http.Header{"Retry-After-Ms": {"10"}},
+		body:   `{"error": {"message": "Rate limit reached", "type": "requests", "code": "rate_limit_exceeded"}}`,
+	}
+	quota := response{
+		status: http.StatusTooManyRequests,
+		body:   `{"error": {"message": "You exceeded your current quota", "type": "insufficient_quota", "code": "insufficient_quota"}}`,
+	}
+	serverError := response{status: http.StatusInternalServerError, body: `{"error": {"message": "The server had an error"}}`}
+	unauthorized := response{status: http.StatusUnauthorized, body: `{"error": {"message": "Incorrect API key provided", "code": "invalid_api_key"}}`}
+	tooLong := response{
+		status: http.StatusBadRequest,
+		body:   `{"error": {"message": "This model's maximum context length is 8192 tokens", "code": "context_length_exceeded"}}`,
+	}
+
+	tests := []struct {
+		name      string
+		responses []response
+		calls     int32
+		check     func(error) bool
+	}{
+		{"429 then 200", []response{rateLimited, ok}, 2, func(err error) bool { return err == nil }},
+		{"500 then 200", []response{serverError, ok}, 2, func(err error) bool { return err == nil }},
+		{"429 until attempts run out", []response{rateLimited}, 3, func(err error) bool {
+			var target *RateLimitError
+			return errors.As(err, &target) && target.RetryAfter == 10*time.Millisecond && target.Code == "rate_limit_exceeded"
+		}},
+		{"500 until attempts run out", []response{serverError}, 3, func(err error) bool {
+			var target *APIError
+			return errors.As(err, &target) && target.StatusCode == http.StatusInternalServerError
+		}},
+		{"insufficient quota is not retried", []response{quota, ok}, 1, func(err error) bool {
+			var target *RateLimitError
+			return errors.As(err, &target) && target.Code == "insufficient_quota"
+		}},
+		{"401 is not retried", []response{unauthorized, ok}, 1, func(err error) bool {
+			var target *AuthError
+			var apiErr *APIError
+			return errors.As(err, &target) && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+		}},
+		{"context length is not retried", []response{tooLong, ok}, 1, func(err error) bool {
+			var target *ContextLengthError
+			return errors.As(err, &target)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := sequenceServer(t, &calls, tt.responses...)
+			client := testOpenAIClient(srv.URL)
+
+			var out map[string]interface{}
+			err := client.post(context.Background(), "/embeddings", "", map[string]string{"input": "halo"}, &out)
+			if !tt.check(err) {
+				t.Errorf("post() error = %v (%T)", err, err)
+			}
+			if calls != tt.calls {
+				t.Errorf("server received %d requests, want %d", calls, tt.calls)
+			}
+			if err == nil && out["ok"] != true {
+				t.Errorf("unexpected response %v", out)
+			}
+		})
+	}
+}
+
+func TestOpenAIClientRetryCanceled(t *testing.T) {
+	var calls int32
+	srv := sequenceServer(t, &calls, response{
+		status: http.StatusTooManyRequests,
+		header: http.Header{"Retry-After": {"30"}},
+		body:   `{"error": {"message": "Rate limit reached"}}`,
+	})
+	client := testOpenAIClient(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	var out map[string]interface{}
+	err := client.post(ctx, "/embeddings", "", map[string]string{"input": "halo"}, &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("post() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("post() returned after %s, want it to stop waiting when canceled", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("server received %d requests, want 1", calls)
+	}
+}
@@ -0,0 +1,217 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxDOCXPartSize membatasi ukuran bagian XML yang dibaca dari arsip DOCX
+const maxDOCXPartSize = 64 << 20
+
+// isDOCX memeriksa apakah arsip ZIP adalah dokumen Word (DOCX)
+func isDOCX(data []byte) bool {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// DOCX mengekstrak teks dokumen Word sebagai Markdown. Paragraf dengan gaya heading menjadi
+// heading Markdown, paragraf daftar menjadi butir daftar, dan tabel menjadi tabel Markdown.
+func DOCX(data []byte) (*Result, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("error opening docx archive: %w", err)
+	}
+
+	var document, core []byte
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			document, err = readZipFile(f)
+		case "docProps/core.xml":
+			core, err = readZipFile(f)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", f.Name, err)
+		}
+	}
+	if document == nil {
+		return nil, fmt.Errorf("docx archive has no word/document.xml")
+	}
+
+	text, err := docxText(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Title:  docxTitle(core),
+		Text:   text,
+		Format: FormatMarkdown,
+	}, nil
+}
+
+// readZipFile membaca isi satu file dalam arsip ZIP dengan batas ukuran
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxDOCXPartSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDOCXPartSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxDOCXPartSize)
+	}
+	return data, nil
+}
+
+// docxText mengubah word/document.xml menjadi Markdown
+func docxText(document []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+
+	var sb strings.Builder
+	var paragraph strings.Builder
+	heading, listLevel := 0, -1
+
+	// Tabel: sel pada baris yang sedang dibaca dan jumlah baris yang sudah ditulis
+	tableDepth := 0
+	var row []string
+	var cell strings.Builder
+	rowsWritten := 0
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error parsing docx document: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				paragraph.Reset()
+				heading, listLevel = 0, -1
+			case "pStyle":
+				heading = docxHeadingLevel(xmlAttr(t, "val"))
+			case "ilvl":
+				listLevel, _ = strconv.Atoi(xmlAttr(t, "val"))
+			case "numPr":
+				if listLevel < 0 {
+					listLevel = 0
+				}
+			case "tab":
+				paragraph.WriteString("\t")
+			case "br", "cr":
+				paragraph.WriteString("\n")
+			case "t":
+				var content string
+				if err := decoder.DecodeElement(&content, &t); err != nil {
+					return "", fmt.Errorf("error parsing docx text: %w", err)
+				}
+				paragraph.WriteString(content)
+			case "tbl":
+				tableDepth++
+				rowsWritten = 0
+				sb.WriteString("\n")
+			case "tr":
+				row = row[:0]
+			case "tc":
+				cell.Reset()
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				if text == "" {
+					continue
+				}
+				if tableDepth > 0 {
+					if cell.Len() > 0 {
+						cell.WriteString(" ")
+					}
+					cell.WriteString(strings.Join(strings.Fields(text), " "))
+					continue
+				}
+				switch {
+				case heading > 0:
+					sb.WriteString(strings.Repeat("#", heading) + " " + text)
+				case listLevel >= 0:
+					sb.WriteString(strings.Repeat("  ", listLevel) + "- " + text)
+				default:
+					sb.WriteString(text)
+				}
+				sb.WriteString("\n\n")
+			case "tc":
+				row = append(row, strings.ReplaceAll(cell.String(), "|", "\\|"))
+			case "tr":
+				sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+				if rowsWritten == 0 {
+					sb.WriteString(strings.Repeat("| --- ", len(row)) + "|\n")
+				}
+				rowsWritten++
+			case "tbl":
+				tableDepth--
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// docxHeadingLevel mengembalikan tingkat heading dari ID gaya paragraf, misalnya "Heading2"
+// atau "Title", atau 0 untuk gaya lain
+func docxHeadingLevel(style string) int {
+	style = strings.ToLower(strings.ReplaceAll(style, " ", ""))
+	if style == "title" {
+		return 1
+	}
+	if level, err := strconv.Atoi(strings.TrimPrefix(style, "heading")); err == nil && strings.HasPrefix(style, "heading") {
+		return min(max(level, 1), 6)
+	}
+	return 0
+}
+
+// docxTitle membaca dc:title dari docProps/core.xml
+func docxTitle(core []byte) string {
+	if core == nil {
+		return ""
+	}
+	var props struct {
+		Title string `xml:"title"`
+	}
+	if err := xml.Unmarshal(core, &props); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(props.Title)
+}
+
+// xmlAttr mengembalikan nilai atribut berdasarkan nama lokalnya
+func xmlAttr(el xml.StartElement, name string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
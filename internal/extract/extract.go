@@ -0,0 +1,131 @@
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tipe MIME yang didukung
+const (
+	MIMEPlainText = "text/plain"
+	MIMEMarkdown  = "text/markdown"
+	MIMEHTML      = "text/html"
+	MIMEPDF       = "application/pdf"
+	MIMEDOCX      = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// Format teks hasil ekstraksi, sama dengan format pada paket chunking
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
+// ErrUnsupportedType dikembalikan saat tipe file tidak dapat diekstrak
+var ErrUnsupportedType = errors.New("unsupported file type")
+
+// Result adalah teks yang diekstrak dari sebuah file
+type Result struct {
+	// Title adalah judul dokumen jika tersedia di file, misalnya <title> HTML
+	Title string
+	Text  string
+	// MIMEType adalah tipe file sumber yang terdeteksi
+	MIMEType string
+	// Format adalah format Text: FormatMarkdown jika struktur heading dipertahankan
+	Format string
+}
+
+// extensionTypes memetakan ekstensi file ke tipe MIME yang didukung
+var extensionTypes = map[string]string{
+	".txt":      MIMEPlainText,
+	".text":     MIMEPlainText,
+	".md":       MIMEMarkdown,
+	".markdown": MIMEMarkdown,
+	".html":     MIMEHTML,
+	".htm":      MIMEHTML,
+	".xhtml":    MIMEHTML,
+	".pdf":      MIMEPDF,
+	".docx":     MIMEDOCX,
+}
+
+// DetectType menentukan tipe MIME file dari isinya, nama file, dan Content-Type yang dikirim
+// klien. Tanda tangan isi file (PDF, ZIP) diutamakan karena ekstensi dan Content-Type dapat salah.
+func DetectType(filename, contentType string, data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return MIMEPDF
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		if isDOCX(data) {
+			return MIMEDOCX
+		}
+		return "application/zip"
+	}
+
+	if t, ok := extensionTypes[strings.ToLower(filepath.Ext(filename))]; ok {
+		return t
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case MIMEPlainText, MIMEMarkdown, MIMEHTML, MIMEPDF, MIMEDOCX:
+			return mediaType
+		case "application/xhtml+xml":
+			return MIMEHTML
+		}
+	}
+
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	return sniffed
+}
+
+// Extract mengekstrak teks dari file sesuai tipenya. File teks lain yang valid (misalnya kode
+// sumber) diperlakukan sebagai teks biasa.
+func Extract(filename, contentType string, data []byte) (*Result, error) {
+	mimeType := DetectType(filename, contentType, data)
+
+	var result *Result
+	var err error
+	switch mimeType {
+	case MIMEPDF:
+		result, err = PDF(data)
+	case MIMEDOCX:
+		result, err = DOCX(data)
+	case MIMEHTML:
+		result, err = HTML(data)
+	case MIMEMarkdown:
+		result = &Result{Text: decodeText(data), Format: FormatMarkdown}
+	case MIMEPlainText:
+		result = &Result{Text: decodeText(data), Format: FormatText}
+	default:
+		return nil, ErrUnsupportedType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.MIMEType = mimeType
+	result.Text = strings.TrimSpace(result.Text)
+	if result.Text == "" {
+		return nil, errors.New("no text could be extracted from the file")
+	}
+	return result, nil
+}
+
+// decodeText mengubah isi file teks menjadi string UTF-8. Byte order mark dibuang dan file
+// yang bukan UTF-8 dianggap Latin-1.
+func decodeText(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
@@ -0,0 +1,131 @@
+package extract
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestExtractFixtures(t *testing.T) {
+	tests := []struct {
+		file        string
+		contentType string
+		mimeType    string
+		format      string
+		title       string
+		text        string
+	}{
+		{
+			file:     "simple.pdf",
+			mimeType: MIMEPDF,
+			format:   FormatText,
+			title:    "Simple Test Document",
+			text: "Getting Started\n" +
+				"Install the package with go get.\n" +
+				"Then run the server.\n" +
+				"\n" +
+				"Second page text\n" +
+				"Café and naïve",
+		},
+		{
+			file:     "handbook.docx",
+			mimeType: MIMEDOCX,
+			format:   FormatMarkdown,
+			title:    "Handbook 2024",
+			text: "# Employee Handbook\n\n" +
+				"# Leave Policy\n\n" +
+				"Employees get 20 days of paid leave.\n\n" +
+				"- Submit requests early\n\n" +
+				"  - At least two weeks ahead\n\n" +
+				"## Holidays\n\n\n" +
+				"| Date | Holiday |\n" +
+				"| --- | --- |\n" +
+				"| 1 January | New Year |\n" +
+				"| 17 August | Independence Day (national \\| public) |\n\n" +
+				"Contact\tHR",
+		},
+		{
+			file:        "page.html",
+			contentType: "text/html; charset=utf-8",
+			mimeType:    MIMEHTML,
+			format:      FormatMarkdown,
+			title:       "Refund Policy | Example Store",
+			text: "# Refund Policy\n\n" +
+				"You can request a refund within 30 days of purchase.\n\n" +
+				"## How to request\n\n" +
+				"- Open your order history.\n" +
+				"- Choose Request refund.\n\n" +
+				"## Processing times\n\n" +
+				"| Method | Days |\n" +
+				"| --- | --- |\n" +
+				"| Card | 5–7 |\n" +
+				"| Bank transfer | 10 |\n\n" +
+				"```\n" +
+				"curl -X POST https://example.com/api/refunds\n" +
+				"  -d order_id=42\n" +
+				"```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			// Nama file tanpa ekstensi memastikan tipe dideteksi dari isi atau Content-Type
+			name := strings.TrimSuffix(tt.file, filepath.Ext(tt.file))
+			result, err := Extract(name, tt.contentType, readFixture(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.MIMEType != tt.mimeType {
+				t.Errorf("MIMEType = %q, want %q", result.MIMEType, tt.mimeType)
+			}
+			if result.Format != tt.format {
+				t.Errorf("Format = %q, want %q", result.Format, tt.format)
+			}
+			if result.Title != tt.title {
+				t.Errorf("Title = %q, want %q", result.Title, tt.title)
+			}
+			if result.Text != tt.text {
+				t.Errorf("Text = %q\nwant   %q", result.Text, tt.text)
+			}
+		})
+	}
+}
+
+func TestExtractHTMLSkipsBoilerplate(t *testing.T) {
+	result, err := HTML(readFixture(t, "page.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, boilerplate := range []string{"Products", "Support", "Home", "Related", "Shipping policy", "All rights reserved", "Privacy", "analytics", "font-family", "Start a refund"} {
+		if strings.Contains(result.Text, boilerplate) {
+			t.Errorf("extracted text contains %q", boilerplate)
+		}
+	}
+}
+
+func TestExtractEncryptedPDF(t *testing.T) {
+	_, err := Extract("encrypted.pdf", MIMEPDF, readFixture(t, "encrypted.pdf"))
+	if err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("Extract() error = %v, want encrypted PDF error", err)
+	}
+}
+
+func TestExtractUnsupported(t *testing.T) {
+	if _, err := Extract("image.png", "image/png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Extract() error = %v, want ErrUnsupportedType", err)
+	}
+	if _, err := Extract("empty.txt", "", []byte(" \n\t ")); err == nil {
+		t.Errorf("Extract() of blank text succeeded, want error")
+	}
+}
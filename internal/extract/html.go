@@ -0,0 +1,447 @@
+package extract
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// htmlSkipElements adalah elemen yang bukan bagian dari konten utama halaman
+var htmlSkipElements = map[string]bool{
+	"script": true, "style": true, "title": true, "noscript": true, "template": true, "svg": true,
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true,
+	"iframe": true, "button": true, "select": true, "dialog": true,
+}
+
+// htmlSkipRoles adalah nilai atribut role yang menandai navigasi dan bagian berulang halaman
+var htmlSkipRoles = map[string]bool{
+	"navigation": true, "banner": true, "contentinfo": true, "complementary": true, "search": true,
+}
+
+// htmlVoidElements adalah elemen tanpa tag penutup
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlRawTextElements adalah elemen yang isinya tidak mengandung tag
+var htmlRawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// htmlBlockElements adalah elemen yang memisahkan teks menjadi baris
+var htmlBlockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true, "br": true, "hr": true,
+	"ul": true, "ol": true, "li": true, "dl": true, "dt": true, "dd": true, "table": true, "tr": true,
+	"blockquote": true, "pre": true, "figure": true, "figcaption": true, "details": true, "summary": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// htmlToken adalah token hasil pemindaian HTML: teks, tag pembuka atau tag penutup
+type htmlToken struct {
+	text  string
+	tag   string
+	attrs map[string]string
+	end   bool
+}
+
+// HTML mengekstrak konten utama halaman HTML sebagai Markdown. Navigasi, header, footer,
+// sidebar, script dan style dibuang; jika halaman memiliki <main> atau <article>, hanya isinya
+// yang diambil. Heading, daftar, tabel dan blok kode dipertahankan agar dapat dipecah per bagian.
+func HTML(data []byte) (*Result, error) {
+	tokens := tokenizeHTML(decodeText(data))
+
+	result := &Result{Format: FormatMarkdown}
+	container := ""
+	for i, tok := range tokens {
+		if tok.tag == "title" && !tok.end && i+1 < len(tokens) && tokens[i+1].tag == "" && result.Title == "" {
+			result.Title = collapseSpace(tokens[i+1].text)
+		}
+		if !tok.end && (tok.tag == "main" || tok.tag == "article") && container != "main" {
+			container = tok.tag
+		}
+	}
+
+	w := &markdownWriter{}
+	skipTag, skipDepth := "", 0
+	inContainer := container == ""
+	containerDepth := 0
+
+	for _, tok := range tokens {
+		// Lewati seluruh isi elemen yang bukan konten utama
+		if skipDepth > 0 {
+			if tok.tag == skipTag {
+				if tok.end {
+					skipDepth--
+				} else if !htmlVoidElements[tok.tag] {
+					skipDepth++
+				}
+			}
+			continue
+		}
+
+		if tok.tag == "" {
+			if inContainer {
+				w.text(tok.text)
+			}
+			continue
+		}
+
+		if !tok.end && (htmlSkipElements[tok.tag] || htmlSkipRoles[tok.attrs["role"]] || tok.attrs["aria-hidden"] == "true") {
+			if !htmlVoidElements[tok.tag] {
+				skipTag, skipDepth = tok.tag, 1
+			}
+			continue
+		}
+
+		if container != "" && tok.tag == container {
+			if !tok.end {
+				containerDepth++
+				inContainer = true
+			} else if containerDepth > 0 {
+				containerDepth--
+				if containerDepth == 0 {
+					w.flush()
+					inContainer = false
+				}
+			}
+		}
+		if inContainer {
+			w.element(tok)
+		}
+	}
+	w.flush()
+
+	result.Text = w.sb.String()
+	return result, nil
+}
+
+// markdownWriter menyusun Markdown dari token HTML
+type markdownWriter struct {
+	sb   strings.Builder
+	line strings.Builder
+	// prefix ditulis sebelum isi baris, misalnya "## " atau "  - "
+	prefix    string
+	preDepth  int
+	listDepth int
+	// rowCells adalah jumlah sel pada baris tabel yang sedang ditulis, rows jumlah baris tabel
+	rowCells int
+	rows     int
+}
+
+// text menambahkan teks. Whitespace digabung kecuali di dalam <pre>.
+func (w *markdownWriter) text(s string) {
+	if w.preDepth > 0 {
+		w.line.WriteString(s)
+		return
+	}
+
+	text := collapseSpace(s)
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return
+	}
+	if unicode.IsSpace(runes[0]) {
+		text = " " + text
+	}
+	if text != " " && unicode.IsSpace(runes[len(runes)-1]) {
+		text += " "
+	}
+	w.line.WriteString(text)
+}
+
+// element menangani tag pembuka atau penutup
+func (w *markdownWriter) element(tok htmlToken) {
+	switch tok.tag {
+	case "pre":
+		if !tok.end {
+			w.flush()
+			w.preDepth++
+			return
+		}
+		if w.preDepth > 0 {
+			w.preDepth--
+			if code := strings.Trim(w.line.String(), "\n"); code != "" {
+				w.sb.WriteString("```\n" + code + "\n```\n\n")
+			}
+			w.line.Reset()
+		}
+		return
+	case "br":
+		if w.preDepth > 0 {
+			w.line.WriteString("\n")
+			return
+		}
+	case "img":
+		if alt := strings.TrimSpace(tok.attrs["alt"]); alt != "" {
+			w.line.WriteString(alt)
+		}
+		return
+	case "td", "th":
+		if !tok.end {
+			if w.rowCells > 0 {
+				w.line.WriteString(" |")
+			}
+			w.line.WriteString(" ")
+			w.rowCells++
+		}
+		return
+	case "table":
+		w.flush()
+		if w.rows > 0 {
+			w.sb.WriteString("\n")
+		}
+		w.rows = 0
+		return
+	case "tr":
+		w.flush()
+		return
+	}
+
+	if w.preDepth > 0 || !htmlBlockElements[tok.tag] {
+		return
+	}
+
+	switch tok.tag {
+	case "ul", "ol":
+		w.flush()
+		if tok.end {
+			w.listDepth = max(w.listDepth-1, 0)
+			if w.listDepth == 0 {
+				w.sb.WriteString("\n")
+			}
+		} else {
+			w.listDepth++
+		}
+		return
+	}
+
+	w.flush()
+	if tok.end {
+		return
+	}
+	if level := headingLevel(tok.tag); level > 0 {
+		w.prefix = strings.Repeat("#", level) + " "
+	} else if tok.tag == "li" {
+		w.prefix = strings.Repeat("  ", max(w.listDepth-1, 0)) + "- "
+	}
+}
+
+// flush menulis baris yang sedang disusun sebagai paragraf, heading, butir daftar atau baris tabel
+func (w *markdownWriter) flush() {
+	text := strings.TrimSpace(w.line.String())
+	w.line.Reset()
+	prefix := w.prefix
+	w.prefix = ""
+
+	if w.rowCells > 0 {
+		cells := w.rowCells
+		w.rowCells = 0
+		w.sb.WriteString("|" + " " + text + " |\n")
+		if w.rows == 0 {
+			w.sb.WriteString(strings.Repeat("| --- ", cells) + "|\n")
+		}
+		w.rows++
+		return
+	}
+	if text == "" {
+		// Pemisah antarbaris tabel tidak mengakhiri tabel
+		return
+	}
+	if w.rows > 0 {
+		// Baris pertama setelah tabel
+		w.sb.WriteString("\n")
+		w.rows = 0
+	}
+
+	w.sb.WriteString(prefix + text)
+	if strings.HasSuffix(prefix, "- ") {
+		w.sb.WriteString("\n")
+	} else {
+		w.sb.WriteString("\n\n")
+	}
+}
+
+// headingLevel mengembalikan tingkat heading h1-h6, atau 0 untuk elemen lain
+func headingLevel(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 0
+}
+
+// tokenizeHTML memindai HTML menjadi token teks dan tag. Pemindai ini toleran terhadap HTML
+// yang tidak valid: tag yang tidak lengkap dianggap teks, dan isi script/style tidak diurai.
+func tokenizeHTML(src string) []htmlToken {
+	var tokens []htmlToken
+	text := func(s string) {
+		if s != "" {
+			tokens = append(tokens, htmlToken{text: html.UnescapeString(s)})
+		}
+	}
+
+	i := 0
+	for i < len(src) {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			text(src[i:])
+			break
+		}
+		text(src[i : i+lt])
+		i += lt
+
+		rest := src[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 3
+			continue
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+			continue
+		}
+
+		tok, n := parseTag(rest)
+		if n == 0 {
+			text("<")
+			i++
+			continue
+		}
+		tokens = append(tokens, tok)
+		i += n
+
+		// Isi elemen raw text berlangsung sampai tag penutupnya
+		if !tok.end && htmlRawTextElements[tok.tag] {
+			end := strings.Index(strings.ToLower(src[i:]), "</"+tok.tag)
+			if end < 0 {
+				end = len(src) - i
+			}
+			if tok.tag == "title" || tok.tag == "textarea" {
+				text(src[i : i+end])
+			} else if end > 0 {
+				tokens = append(tokens, htmlToken{text: src[i : i+end]})
+			}
+			i += end
+		}
+	}
+
+	return tokens
+}
+
+// parseTag mengurai tag di awal s dan mengembalikan panjangnya, atau 0 jika s bukan tag
+func parseTag(s string) (htmlToken, int) {
+	var tok htmlToken
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		tok.end = true
+		i++
+	}
+
+	start := i
+	for i < len(s) && isTagNameChar(s[i]) {
+		i++
+	}
+	if i == start {
+		return tok, 0
+	}
+	tok.tag = strings.ToLower(s[start:i])
+
+	// Atribut: nama, nama=nilai, nama="nilai" atau nama='nilai'
+	for i < len(s) {
+		for i < len(s) && (isSpace(s[i]) || s[i] == '/') {
+			i++
+		}
+		if i >= len(s) {
+			return tok, 0
+		}
+		if s[i] == '>' {
+			return tok, i + 1
+		}
+
+		nameStart := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		value := ""
+
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				quote := s[i]
+				end := strings.IndexByte(s[i+1:], quote)
+				if end < 0 {
+					return tok, 0
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				value = s[valueStart:i]
+			}
+		}
+
+		if name != "" {
+			if tok.attrs == nil {
+				tok.attrs = make(map[string]string)
+			}
+			tok.attrs[name] = html.UnescapeString(value)
+		}
+	}
+
+	return tok, 0
+}
+
+// isTagNameChar memeriksa apakah c dapat menjadi bagian dari nama tag
+func isTagNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == ':'
+}
+
+// isSpace memeriksa apakah c adalah whitespace HTML
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// collapseSpace menggabungkan whitespace berurutan menjadi satu spasi
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// HTMLLinks mengembalikan nilai href semua tautan <a> di halaman beserta href <base> jika ada.
+// Tautan dengan rel="nofollow" dilewati.
+func HTMLLinks(data []byte) (links []string, base string) {
+	for _, tok := range tokenizeHTML(decodeText(data)) {
+		if tok.end {
+			continue
+		}
+		switch tok.tag {
+		case "base":
+			if base == "" {
+				base = strings.TrimSpace(tok.attrs["href"])
+			}
+		case "a":
+			href := strings.TrimSpace(tok.attrs["href"])
+			if href == "" || strings.Contains(strings.ToLower(tok.attrs["rel"]), "nofollow") {
+				continue
+			}
+			links = append(links, href)
+		}
+	}
+	return links, base
+}
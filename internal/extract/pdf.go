@@ -0,0 +1,837 @@
+package extract
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxPDFStreamSize membatasi ukuran stream PDF setelah didekompresi
+const maxPDFStreamSize = 64 << 20
+
+var (
+	pdfObjectPattern   = regexp.MustCompile(`(?s)(\d+)\s+(\d+)\s+obj\b(.*?)\bendobj`)
+	pdfRefPattern      = regexp.MustCompile(`(\d+)\s+\d+\s+R\b`)
+	pdfLeadingRef      = regexp.MustCompile(`^(\d+)\s+\d+\s+R\b`)
+	pdfFontDictPattern = regexp.MustCompile(`(?s)/Font\s*<<(.*?)>>`)
+	pdfFontRefPattern  = regexp.MustCompile(`/Font\s+(\d+)\s+\d+\s+R\b`)
+	pdfNamedRefPattern = regexp.MustCompile(`/([^\s/<>\[\]()]+)\s+(\d+)\s+\d+\s+R\b`)
+	pdfToUnicode       = regexp.MustCompile(`/ToUnicode\s+(\d+)\s+\d+\s+R\b`)
+)
+
+// pdfUnsupportedFilters adalah filter stream yang tidak dapat didekode, biasanya gambar
+var pdfUnsupportedFilters = []string{
+	"/ASCIIHexDecode", "/ASCII85Decode", "/LZWDecode", "/RunLengthDecode",
+	"/CCITTFaxDecode", "/JBIG2Decode", "/DCTDecode", "/JPXDecode", "/Crypt",
+}
+
+// pdfDocument adalah objek-objek PDF yang sudah diindeks berdasarkan nomornya
+type pdfDocument struct {
+	objects map[int][]byte
+	// fonts memetakan nomor objek font ke CMap ToUnicode-nya
+	fonts map[int]*pdfCMap
+	// globalFonts memetakan nama font ke CMap dari semua resource di file, dipakai jika
+	// resource halaman tidak ditemukan (misalnya diwarisi dari induknya)
+	globalFonts map[string]*pdfCMap
+}
+
+// PDF mengekstrak teks dari file PDF. Hanya stream tanpa filter atau dengan FlateDecode yang
+// didukung, dan teks dipetakan ke Unicode melalui CMap ToUnicode font jika tersedia.
+// PDF hasil pindaian (gambar) dan PDF terenkripsi tidak dapat diekstrak.
+func PDF(data []byte) (*Result, error) {
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return nil, errors.New("encrypted PDF files are not supported")
+	}
+
+	doc := &pdfDocument{
+		objects:     make(map[int][]byte),
+		fonts:       make(map[int]*pdfCMap),
+		globalFonts: make(map[string]*pdfCMap),
+	}
+	doc.indexObjects(data)
+	doc.loadFonts()
+
+	var sb strings.Builder
+	pages := doc.pages()
+	if len(pages) > 0 {
+		for _, page := range pages {
+			fonts := doc.pageFonts(page)
+			for _, ref := range doc.contentRefs(page) {
+				if content, ok := doc.stream(ref); ok {
+					sb.WriteString(pdfContentText(content, fonts, doc.globalFonts))
+					sb.WriteString("\n")
+				}
+			}
+			sb.WriteString("\n")
+		}
+	} else {
+		// Tanpa pohon halaman, ambil semua stream yang berisi operator teks
+		for _, num := range doc.objectNumbers() {
+			if content, ok := doc.stream(num); ok && bytes.Contains(content, []byte("BT")) && !bytes.Contains(content, []byte("begincmap")) {
+				sb.WriteString(pdfContentText(content, nil, doc.globalFonts))
+				sb.WriteString("\n\n")
+			}
+		}
+	}
+
+	return &Result{
+		Title:  doc.title(),
+		Text:   cleanPDFText(sb.String()),
+		Format: FormatText,
+	}, nil
+}
+
+// indexObjects mencari semua objek "N G obj ... endobj", termasuk objek di dalam object stream
+func (d *pdfDocument) indexObjects(data []byte) {
+	for _, m := range pdfObjectPattern.FindAllSubmatch(data, -1) {
+		num, _ := strconv.Atoi(string(m[1]))
+		d.objects[num] = m[3]
+	}
+
+	// Object stream (PDF 1.5) menyimpan objek terkompresi, misalnya kamus font dan halaman
+	for _, num := range d.objectNumbers() {
+		body := d.objects[num]
+		if !bytes.Contains(pdfDict(body), []byte("/ObjStm")) {
+			continue
+		}
+		content, ok := d.stream(num)
+		if !ok {
+			continue
+		}
+
+		first := pdfDictInt(pdfDict(body), "/First")
+		if first <= 0 || first > len(content) {
+			continue
+		}
+		header := strings.Fields(string(content[:first]))
+		for i := 0; i+1 < len(header); i += 2 {
+			objNum, err1 := strconv.Atoi(header[i])
+			offset, err2 := strconv.Atoi(header[i+1])
+			if err1 != nil || err2 != nil || first+offset > len(content) {
+				continue
+			}
+			end := len(content)
+			if i+3 < len(header) {
+				if next, err := strconv.Atoi(header[i+3]); err == nil && first+next <= len(content) && next >= offset {
+					end = first + next
+				}
+			}
+			if _, exists := d.objects[objNum]; !exists {
+				d.objects[objNum] = content[first+offset : end]
+			}
+		}
+	}
+}
+
+// objectNumbers mengembalikan nomor objek secara berurutan
+func (d *pdfDocument) objectNumbers() []int {
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// stream mengembalikan isi stream objek num yang sudah didekompresi
+func (d *pdfDocument) stream(num int) ([]byte, bool) {
+	body, ok := d.objects[num]
+	if !ok {
+		return nil, false
+	}
+
+	idx := bytes.Index(body, []byte("stream"))
+	if idx < 0 {
+		return nil, false
+	}
+	dict := body[:idx]
+	raw := body[idx+len("stream"):]
+	raw = bytes.TrimPrefix(raw, []byte("\r"))
+	raw = bytes.TrimPrefix(raw, []byte("\n"))
+	if end := bytes.LastIndex(raw, []byte("endstream")); end >= 0 {
+		raw = raw[:end]
+	}
+	raw = bytes.TrimRight(raw, "\r\n")
+
+	// Hanya FlateDecode yang didukung; gambar dan filter lain dilewati
+	if bytes.Contains(dict, []byte("/Filter")) {
+		if !bytes.Contains(dict, []byte("/FlateDecode")) {
+			return nil, false
+		}
+		for _, filter := range pdfUnsupportedFilters {
+			if bytes.Contains(dict, []byte(filter)) {
+				return nil, false
+			}
+		}
+		return inflate(raw)
+	}
+	return raw, true
+}
+
+// inflate mendekompresi data FlateDecode. Data yang rusak di bagian akhir tetap dikembalikan
+// sejauh yang dapat dibaca.
+func inflate(raw []byte) ([]byte, bool) {
+	var r io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(raw)); err == nil {
+		defer zr.Close()
+		r = zr
+	} else if len(raw) > 2 {
+		fr := flate.NewReader(bytes.NewReader(raw[2:]))
+		defer fr.Close()
+		r = fr
+	} else {
+		return nil, false
+	}
+
+	out, err := io.ReadAll(io.LimitReader(r, maxPDFStreamSize))
+	if err != nil && len(out) == 0 {
+		return nil, false
+	}
+	return out, true
+}
+
+// loadFonts membaca CMap ToUnicode setiap font dan memetakan nama font di semua resource
+func (d *pdfDocument) loadFonts() {
+	for num, body := range d.objects {
+		if m := pdfToUnicode.FindSubmatch(pdfDict(body)); m != nil {
+			ref, _ := strconv.Atoi(string(m[1]))
+			if content, ok := d.stream(ref); ok {
+				d.fonts[num] = parsePDFCMap(content)
+			}
+		}
+	}
+
+	for _, num := range d.objectNumbers() {
+		for name, cmap := range d.fontResources(pdfDict(d.objects[num])) {
+			if _, exists := d.globalFonts[name]; !exists {
+				d.globalFonts[name] = cmap
+			}
+		}
+	}
+}
+
+// fontResources membaca kamus /Font di dalam body dan memetakan nama font ke CMap-nya
+func (d *pdfDocument) fontResources(body []byte) map[string]*pdfCMap {
+	fonts := make(map[string]*pdfCMap)
+	add := func(dict []byte) {
+		for _, m := range pdfNamedRefPattern.FindAllSubmatch(dict, -1) {
+			ref, _ := strconv.Atoi(string(m[2]))
+			if cmap, ok := d.fonts[ref]; ok {
+				fonts[string(m[1])] = cmap
+			}
+		}
+	}
+
+	for _, m := range pdfFontDictPattern.FindAllSubmatch(body, -1) {
+		add(m[1])
+	}
+	for _, m := range pdfFontRefPattern.FindAllSubmatch(body, -1) {
+		ref, _ := strconv.Atoi(string(m[1]))
+		add(d.objects[ref])
+	}
+	return fonts
+}
+
+// pages mengembalikan nomor objek halaman sesuai urutan di pohon halaman
+func (d *pdfDocument) pages() []int {
+	var root int
+	for _, num := range d.objectNumbers() {
+		if dict := pdfDict(d.objects[num]); pdfDictName(dict, "/Type") == "Catalog" {
+			root = pdfDictRef(dict, "/Pages")
+			break
+		}
+	}
+	if root == 0 {
+		return nil
+	}
+
+	var pages []int
+	visited := make(map[int]bool)
+	var walk func(num int)
+	walk = func(num int) {
+		if visited[num] {
+			return
+		}
+		visited[num] = true
+
+		dict := pdfDict(d.objects[num])
+		switch pdfDictName(dict, "/Type") {
+		case "Page":
+			pages = append(pages, num)
+		case "Pages":
+			for _, kid := range pdfDictRefs(dict, "/Kids") {
+				walk(kid)
+			}
+		}
+	}
+	walk(root)
+	return pages
+}
+
+// pageFonts mengembalikan CMap font yang dideklarasikan di resource halaman
+func (d *pdfDocument) pageFonts(page int) map[string]*pdfCMap {
+	dict := pdfDict(d.objects[page])
+	if ref := pdfDictRef(dict, "/Resources"); ref > 0 {
+		return d.fontResources(pdfDict(d.objects[ref]))
+	}
+	return d.fontResources(dict)
+}
+
+// contentRefs mengembalikan nomor objek content stream sebuah halaman
+func (d *pdfDocument) contentRefs(page int) []int {
+	dict := pdfDict(d.objects[page])
+	refs := pdfDictRefs(dict, "/Contents")
+	if len(refs) == 1 {
+		// /Contents dapat merujuk ke array berisi referensi stream
+		if body := d.objects[refs[0]]; !bytes.Contains(body, []byte("stream")) {
+			if nested := pdfRefPattern.FindAllSubmatch(body, -1); len(nested) > 0 {
+				refs = refs[:0]
+				for _, m := range nested {
+					ref, _ := strconv.Atoi(string(m[1]))
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// title membaca judul dari kamus /Info dokumen
+func (d *pdfDocument) title() string {
+	for _, num := range d.objectNumbers() {
+		dict := pdfDict(d.objects[num])
+		value := pdfDictValue(dict, "/Title")
+		if value == nil || (pdfDictValue(dict, "/Producer") == nil && pdfDictValue(dict, "/Creator") == nil) {
+			continue
+		}
+		lex := &pdfLexer{data: value}
+		if tok := lex.next(); tok.kind == pdfString {
+			return strings.TrimSpace(decodePDFString(tok.str, nil))
+		}
+	}
+	return ""
+}
+
+// pdfDict mengembalikan bagian kamus objek (sebelum kata kunci stream)
+func pdfDict(body []byte) []byte {
+	if idx := bytes.Index(body, []byte("stream")); idx >= 0 {
+		return body[:idx]
+	}
+	return body
+}
+
+// pdfDictValue mengembalikan teks setelah kunci kamus, misalnya " /Page" untuk kunci /Type
+func pdfDictValue(dict []byte, key string) []byte {
+	for offset := 0; ; {
+		idx := bytes.Index(dict[offset:], []byte(key))
+		if idx < 0 {
+			return nil
+		}
+		end := offset + idx + len(key)
+		// Pastikan kunci tidak hanya awalan kunci lain, misalnya /Type dan /Type0
+		if end >= len(dict) || isPDFSpace(dict[end]) || isPDFDelimiter(dict[end]) {
+			return bytes.TrimLeft(dict[end:], " \t\r\n\f\x00")
+		}
+		offset = end
+	}
+}
+
+// pdfDictName membaca nilai nama dari kunci kamus, misalnya /Type /Page menjadi "Page"
+func pdfDictName(dict []byte, key string) string {
+	value := pdfDictValue(dict, key)
+	if len(value) == 0 || value[0] != '/' {
+		return ""
+	}
+	lex := &pdfLexer{data: value[1:]}
+	return lex.regular()
+}
+
+// pdfDictInt membaca nilai bilangan bulat dari kunci kamus
+func pdfDictInt(dict []byte, key string) int {
+	value := pdfDictValue(dict, key)
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(string(value[:end]))
+	return n
+}
+
+// pdfDictRef membaca referensi objek dari kunci kamus
+func pdfDictRef(dict []byte, key string) int {
+	if m := pdfLeadingRef.FindSubmatch(pdfDictValue(dict, key)); m != nil {
+		n, _ := strconv.Atoi(string(m[1]))
+		return n
+	}
+	return 0
+}
+
+// pdfDictRefs membaca satu referensi atau array referensi dari kunci kamus
+func pdfDictRefs(dict []byte, key string) []int {
+	value := pdfDictValue(dict, key)
+	if len(value) > 0 && value[0] == '[' {
+		if end := bytes.IndexByte(value, ']'); end >= 0 {
+			var refs []int
+			for _, m := range pdfRefPattern.FindAllSubmatch(value[:end], -1) {
+				n, _ := strconv.Atoi(string(m[1]))
+				refs = append(refs, n)
+			}
+			return refs
+		}
+		return nil
+	}
+	if ref := pdfDictRef(dict, key); ref > 0 {
+		return []int{ref}
+	}
+	return nil
+}
+
+// pdfCMap memetakan kode karakter font ke teks Unicode
+type pdfCMap struct {
+	// width adalah jumlah byte per kode karakter
+	width int
+	codes map[uint32]string
+}
+
+// parsePDFCMap mengurai CMap ToUnicode (bfchar dan bfrange)
+func parsePDFCMap(data []byte) *pdfCMap {
+	cmap := &pdfCMap{width: 1, codes: make(map[uint32]string)}
+	lex := &pdfLexer{data: data}
+
+	var operands []pdfToken
+	for {
+		tok := lex.next()
+		if tok.kind == pdfEOF {
+			break
+		}
+		if tok.kind != pdfOperator {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch tok.op {
+		case "endcodespacerange":
+			if len(operands) > 0 && operands[0].kind == pdfString && len(operands[0].str) > 0 {
+				cmap.width = len(operands[0].str)
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				cmap.codes[pdfCode(operands[i].str)] = decodeUTF16(operands[i+1].str)
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, hi := pdfCode(operands[i].str), pdfCode(operands[i+1].str)
+				if hi < lo || hi-lo > 0xFFFF {
+					continue
+				}
+				dst := operands[i+2]
+				for code := lo; code <= hi; code++ {
+					switch {
+					case dst.kind == pdfArray && int(code-lo) < len(dst.arr):
+						cmap.codes[code] = decodeUTF16(dst.arr[code-lo].str)
+					case dst.kind == pdfString && len(dst.str) > 0:
+						// Kode berurutan dipetakan ke karakter berurutan mulai dari dst
+						units := utf16.Decode(bytesToUTF16(dst.str))
+						if len(units) > 0 {
+							units[len(units)-1] += rune(code - lo)
+						}
+						cmap.codes[code] = string(units)
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+
+	return cmap
+}
+
+// pdfCode mengubah byte kode karakter menjadi bilangan
+func pdfCode(b []byte) uint32 {
+	var code uint32
+	for _, c := range b {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+// bytesToUTF16 mengubah byte UTF-16BE menjadi unit UTF-16
+func bytesToUTF16(b []byte) []uint16 {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return units
+}
+
+// decodeUTF16 mengubah byte UTF-16BE menjadi string
+func decodeUTF16(b []byte) string {
+	return string(utf16.Decode(bytesToUTF16(b)))
+}
+
+// winAnsiExtra memetakan byte 0x80-0x9F WinAnsiEncoding yang berbeda dari Latin-1
+var winAnsiExtra = map[byte]rune{
+	0x80: '€', 0x85: '…', 0x86: '†', 0x87: '‡', 0x89: '‰', 0x8A: 'Š', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x98: '˜',
+	0x99: '™', 0x9A: 'š', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// decodePDFString mengubah string PDF menjadi teks menggunakan CMap font jika ada. Tanpa CMap,
+// string dengan BOM UTF-16 didekode sebagai UTF-16 dan selebihnya sebagai WinAnsiEncoding.
+func decodePDFString(b []byte, cmap *pdfCMap) string {
+	if cmap != nil && len(cmap.codes) > 0 {
+		var sb strings.Builder
+		for i := 0; i+cmap.width <= len(b); i += cmap.width {
+			code := pdfCode(b[i : i+cmap.width])
+			if text, ok := cmap.codes[code]; ok {
+				sb.WriteString(text)
+			} else if cmap.width == 1 {
+				sb.WriteRune(rune(code))
+			}
+		}
+		return sb.String()
+	}
+
+	if bytes.HasPrefix(b, []byte{0xFE, 0xFF}) {
+		return decodeUTF16(b[2:])
+	}
+
+	runes := make([]rune, 0, len(b))
+	for _, c := range b {
+		if r, ok := winAnsiExtra[c]; ok {
+			runes = append(runes, r)
+		} else {
+			runes = append(runes, rune(c))
+		}
+	}
+	return string(runes)
+}
+
+// pdfContentText menjalankan operator teks content stream dan mengembalikan teks yang ditampilkan
+func pdfContentText(content []byte, fonts, globalFonts map[string]*pdfCMap) string {
+	var sb strings.Builder
+	newline := func() {
+		if s := sb.String(); s != "" && !strings.HasSuffix(s, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	space := func() {
+		if s := sb.String(); s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+			sb.WriteString(" ")
+		}
+	}
+
+	var cmap *pdfCMap
+	show := func(tok pdfToken) {
+		if tok.kind == pdfString {
+			sb.WriteString(decodePDFString(tok.str, cmap))
+		}
+	}
+
+	lex := &pdfLexer{data: content}
+	var operands []pdfToken
+	lastY, hasY := 0.0, false
+
+	for {
+		tok := lex.next()
+		if tok.kind == pdfEOF {
+			break
+		}
+		if tok.kind != pdfOperator {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch tok.op {
+		case "ET":
+			newline()
+		case "Tf":
+			if len(operands) >= 2 {
+				name := operands[len(operands)-2].op
+				cmap = fonts[name]
+				if cmap == nil {
+					cmap = globalFonts[name]
+				}
+			}
+		case "Tj":
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "'", "\"":
+			newline()
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "TJ":
+			if len(operands) > 0 && operands[len(operands)-1].kind == pdfArray {
+				for _, el := range operands[len(operands)-1].arr {
+					if el.kind == pdfNumber && el.num < -180 {
+						// Jarak antarhuruf yang besar menandai spasi antarkata
+						space()
+					}
+					show(el)
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if operands[len(operands)-1].num != 0 {
+					newline()
+				} else if operands[len(operands)-2].num > 0 {
+					space()
+				}
+			}
+		case "T*":
+			newline()
+		case "Tm":
+			if len(operands) >= 6 {
+				y := operands[len(operands)-1].num
+				if hasY && y != lastY {
+					newline()
+				} else if hasY {
+					space()
+				}
+				lastY, hasY = y, true
+			}
+		case "BI":
+			lex.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+
+	return sb.String()
+}
+
+// cleanPDFText merapikan spasi di setiap baris dan membuang baris kosong berlebih
+func cleanPDFText(text string) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	blank := 0
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank++
+			if blank > 1 {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// Jenis token content stream PDF
+const (
+	pdfEOF = iota
+	pdfNumber
+	pdfString
+	pdfName
+	pdfArray
+	pdfOperator
+)
+
+// pdfToken adalah satu token content stream: angka, string, nama, array atau operator
+type pdfToken struct {
+	kind int
+	num  float64
+	str  []byte
+	// op adalah nama operator, atau nama tanpa garis miring untuk token pdfName
+	op  string
+	arr []pdfToken
+}
+
+// pdfLexer memindai token dari content stream atau CMap PDF
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+// next mengembalikan token berikutnya
+func (l *pdfLexer) next() pdfToken {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '(':
+			return pdfToken{kind: pdfString, str: l.literalString()}
+		case c == '<':
+			if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+				// Kamus inline (misalnya properti marked content) diabaikan
+				l.pos += 2
+				continue
+			}
+			return pdfToken{kind: pdfString, str: l.hexString()}
+		case c == '>':
+			l.pos++
+		case c == '[':
+			l.pos++
+			var arr []pdfToken
+			for {
+				tok := l.next()
+				if tok.kind == pdfEOF || (tok.kind == pdfOperator && tok.op == "]") {
+					break
+				}
+				arr = append(arr, tok)
+			}
+			return pdfToken{kind: pdfArray, arr: arr}
+		case c == ']':
+			l.pos++
+			return pdfToken{kind: pdfOperator, op: "]"}
+		case c == '/':
+			l.pos++
+			return pdfToken{kind: pdfName, op: l.regular()}
+		default:
+			word := l.regular()
+			if word == "" {
+				l.pos++
+				continue
+			}
+			if num, err := strconv.ParseFloat(word, 64); err == nil {
+				return pdfToken{kind: pdfNumber, num: num}
+			}
+			return pdfToken{kind: pdfOperator, op: word}
+		}
+	}
+	return pdfToken{kind: pdfEOF}
+}
+
+// regular membaca karakter reguler sampai whitespace atau delimiter
+func (l *pdfLexer) regular() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// literalString membaca string (...) dengan tanda kurung bersarang dan escape
+func (l *pdfLexer) literalString() []byte {
+	l.pos++ // lewati '('
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+			out = append(out, c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				// Kelanjutan baris
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					code := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						code = code*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(code))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// hexString membaca string <...> dalam heksadesimal
+func (l *pdfLexer) hexString() []byte {
+	l.pos++ // lewati '<'
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; isHexDigit(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // lewati '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		v, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		out[i] = byte(v)
+	}
+	return out
+}
+
+// skipInlineImage melewati data gambar inline sampai operator EI
+func (l *pdfLexer) skipInlineImage() {
+	idx := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += idx + 2
+	for l.pos < len(l.data) {
+		idx := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if idx < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		l.pos += idx + 2
+		if isPDFSpace(l.data[l.pos-3]) && (l.pos >= len(l.data) || isPDFSpace(l.data[l.pos])) {
+			return
+		}
+	}
+}
+
+// isPDFSpace memeriksa apakah c adalah whitespace PDF
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+// isPDFDelimiter memeriksa apakah c adalah delimiter PDF
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// isHexDigit memeriksa apakah c adalah digit heksadesimal
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"rag-chat-bot/internal/crawler"
+	"rag-chat-bot/internal/extract"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/rag"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kunci metadata dokumen hasil crawling
+const (
+	metadataSourceURL    = "source_url"
+	metadataLastModified = "last_modified"
+	metadataETag         = "etag"
+)
+
+// CrawlSiteHandler membuat handler job crawl_site yang meng-crawl CrawlRequest dan menyimpan
+// setiap halaman melalui Processor.SyncDocument dengan kunci source_url. Halaman yang ETag atau
+// Last-Modified-nya tidak berubah dilewati dengan permintaan bersyarat, dan halaman yang hash
+// kontennya sama tidak di-embed ulang.
+func CrawlSiteHandler(processor *rag.Processor, opts crawler.Options) Handler {
+	return func(ctx context.Context, job *model.Job, progress ProgressFunc) (interface{}, error) {
+		var req model.CrawlRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return nil, Permanent(fmt.Errorf("error decoding job payload: %w", err))
+		}
+
+		crawlOpts := opts
+		if req.MaxDepth != nil {
+			crawlOpts.MaxDepth = *req.MaxDepth
+		}
+		if req.MaxPages > 0 {
+			crawlOpts.MaxPages = req.MaxPages
+		}
+		if len(req.AllowedDomains) > 0 {
+			crawlOpts.AllowedDomains = req.AllowedDomains
+		}
+		crawlOpts.Validators = func(ctx context.Context, pageURL string) (string, string) {
+			doc, err := processor.FindDocumentByMetadata(ctx, metadataSourceURL, pageURL)
+			if err != nil {
+				return "", ""
+			}
+			etag, _ := doc.Metadata[metadataETag].(string)
+			lastModified := ""
+			if v, ok := doc.Metadata[metadataLastModified].(string); ok {
+				if t, err := time.Parse(time.RFC3339, v); err == nil {
+					lastModified = t.UTC().Format(http.TimeFormat)
+				}
+			}
+			return etag, lastModified
+		}
+
+		var mu sync.Mutex
+		var result model.CrawlResult
+		visit := func(ctx context.Context, page *crawler.Page) error {
+			// Halaman tanpa teks (misalnya hanya berisi gambar) tidak perlu disimpan
+			if strings.TrimSpace(page.Text) == "" {
+				mu.Lock()
+				result.Skipped++
+				mu.Unlock()
+				return nil
+			}
+
+			metadata := make(map[string]interface{}, len(req.Metadata)+6)
+			maps.Copy(metadata, req.Metadata)
+			metadata["mime_type"] = page.MIMEType
+			metadata["crawled_at"] = time.Now().UTC().Format(time.RFC3339)
+			if page.Format == extract.FormatMarkdown {
+				// Halaman HTML dikonversi ke Markdown sehingga dipecah per bagian
+				metadata["format"] = extract.FormatMarkdown
+			}
+			if page.ETag != "" {
+				metadata[metadataETag] = page.ETag
+			}
+			if !page.LastModified.IsZero() {
+				metadata[metadataLastModified] = page.LastModified.UTC().Format(time.RFC3339)
+			}
+
+			title := page.Title
+			if title == "" {
+				title = page.URL
+			}
+
+			action, _, err := processor.SyncDocument(ctx, metadataSourceURL, page.URL, &model.Document{
+				Title:    title,
+				Content:  page.Text,
+				Metadata: metadata,
+			})
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch action {
+			case rag.SyncCreated:
+				result.Created++
+			case rag.SyncUpdated:
+				result.Updated++
+			case rag.SyncDuplicate:
+				result.Duplicates++
+			default:
+				result.Unchanged++
+			}
+			return nil
+		}
+
+		stats, err := crawler.New(crawlOpts).Crawl(ctx, req.URLs, req.Sitemaps, visit, func(done, total int) {
+			progress("crawling", done, total)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Discovered = stats.Discovered
+		result.NotModified = stats.NotModified
+		result.Skipped += stats.Skipped
+		result.Failed = stats.Failed
+		return result, nil
+	}
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rag-chat-bot/internal/embedding"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/rag"
+)
+
+// IngestDocumentHandler membuat handler job ingest_document yang memproses
+// CreateDocumentRequest melalui Processor. Hasil job adalah model.IngestResult.
+func IngestDocumentHandler(processor *rag.Processor) Handler {
+	return func(ctx context.Context, job *model.Job, progress ProgressFunc) (interface{}, error) {
+		var req model.CreateDocumentRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return nil, Permanent(fmt.Errorf("error decoding job payload: %w", err))
+		}
+
+		doc := &model.Document{
+			Title:    req.Title,
+			Content:  req.Content,
+			Metadata: req.Metadata,
+		}
+
+		result, err := processor.IngestDocument(ctx, doc, req.OnDuplicate, rag.ProgressFunc(progress))
+		if err != nil {
+			// Error seperti kunci API salah, konten terlalu panjang atau dokumen kosong tidak akan
+			// pulih dengan mencoba ulang
+			if errors.Is(err, rag.ErrEmptyDocument) || !embedding.IsRetryable(err) {
+				return nil, Permanent(err)
+			}
+			return nil, err
+		}
+
+		return result, nil
+	}
+}
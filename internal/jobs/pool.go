@@ -0,0 +1,293 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"rag-chat-bot/internal/model"
+	"sync"
+	"time"
+)
+
+// Handler memproses satu job dan mengembalikan hasil yang disimpan sebagai JSON.
+// Kemajuan dapat dilaporkan melalui progress.
+type Handler func(ctx context.Context, job *model.Job, progress ProgressFunc) (interface{}, error)
+
+// ProgressFunc melaporkan tahap job yang sedang berjalan beserta kemajuannya
+type ProgressFunc func(stage string, done, total int)
+
+// permanentError menandai error yang tidak akan pulih dengan mencoba ulang
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent membungkus err agar job langsung gagal tanpa dicoba ulang
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Options mengatur worker pool
+type Options struct {
+	// Workers adalah jumlah job yang diproses bersamaan
+	Workers int
+	// MaxAttempts adalah jumlah maksimum percobaan untuk setiap job baru
+	MaxAttempts int
+	// PollInterval adalah jeda memeriksa antrean saat tidak ada job
+	PollInterval time.Duration
+	// RetryBaseDelay adalah jeda awal sebelum job yang gagal dicoba ulang, berlipat ganda setiap percobaan
+	RetryBaseDelay time.Duration
+	// StaleAfter adalah waktu tanpa pembaruan sebelum job running dianggap ditinggalkan
+	StaleAfter time.Duration
+}
+
+// Queue adalah antrean job yang digunakan Pool, diimplementasikan oleh database.PostgresDB
+type Queue interface {
+	EnqueueJob(ctx context.Context, kind string, payload interface{}, maxAttempts int) (int, error)
+	ClaimJob(ctx context.Context, kinds []string, staleAfter time.Duration) (*model.Job, error)
+	UpdateJobProgress(ctx context.Context, jobID int, stage string, done, total int) error
+	TouchJob(ctx context.Context, jobID int) error
+	CompleteJob(ctx context.Context, jobID int, result interface{}) error
+	FailJob(ctx context.Context, jobID int, jobErr error, retryAfter time.Duration) error
+	ReleaseJob(ctx context.Context, jobID int) error
+	GetJob(ctx context.Context, jobID int) (*model.Job, error)
+}
+
+// Pool menjalankan worker yang mengambil job dari antrean ingestion_jobs di PostgreSQL
+type Pool struct {
+	db       Queue
+	opts     Options
+	handlers map[string]Handler
+	wake     chan struct{}
+
+	mu      sync.Mutex
+	stop    context.CancelFunc
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewPool membuat worker pool baru. Handler untuk setiap jenis job didaftarkan dengan Register
+// sebelum Start dipanggil.
+func NewPool(db Queue, opts Options) *Pool {
+	if opts.Workers <= 0 {
+		opts.Workers = 2 // Default value
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3 // Default value
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.RetryBaseDelay <= 0 {
+		opts.RetryBaseDelay = 30 * time.Second
+	}
+	if opts.StaleAfter <= 0 {
+		opts.StaleAfter = 10 * time.Minute
+	}
+
+	return &Pool{
+		db:       db,
+		opts:     opts,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register mendaftarkan handler untuk jenis job tertentu
+func (p *Pool) Register(kind string, handler Handler) {
+	p.handlers[kind] = handler
+}
+
+// Enqueue menambahkan job ke antrean dan membangunkan worker yang sedang menunggu
+func (p *Pool) Enqueue(ctx context.Context, kind string, payload interface{}) (int, error) {
+	if _, ok := p.handlers[kind]; !ok {
+		return 0, fmt.Errorf("no handler registered for job kind %q", kind)
+	}
+
+	jobID, err := p.db.EnqueueJob(ctx, kind, payload, p.opts.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return jobID, nil
+}
+
+// Get mengambil status job berdasarkan ID
+func (p *Pool) Get(ctx context.Context, jobID int) (*model.Job, error) {
+	return p.db.GetJob(ctx, jobID)
+}
+
+// Start menjalankan worker di goroutine terpisah
+func (p *Pool) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return
+	}
+	p.started = true
+
+	// stopCtx menghentikan pengambilan job baru, jobCtx membatalkan job yang sedang berjalan
+	stopCtx, stop := context.WithCancel(context.Background())
+	jobCtx, cancel := context.WithCancel(context.Background())
+	p.stop = stop
+	p.cancel = cancel
+
+	kinds := make([]string, 0, len(p.handlers))
+	for kind := range p.handlers {
+		kinds = append(kinds, kind)
+	}
+
+	for i := 0; i < p.opts.Workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(stopCtx, jobCtx, kinds)
+		}()
+	}
+	log.Printf("Started %d job workers", p.opts.Workers)
+}
+
+// Shutdown berhenti mengambil job baru dan menunggu job yang sedang berjalan selesai.
+// Jika ctx berakhir lebih dulu, job yang tersisa dibatalkan dan dikembalikan ke antrean.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.started {
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	p.stop()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work mengambil dan menjalankan job sampai stopCtx dibatalkan
+func (p *Pool) work(stopCtx, jobCtx context.Context, kinds []string) {
+	for {
+		if stopCtx.Err() != nil {
+			return
+		}
+
+		job, err := p.db.ClaimJob(stopCtx, kinds, p.opts.StaleAfter)
+		if err != nil && stopCtx.Err() == nil {
+			log.Printf("Error claiming job: %v", err)
+		}
+
+		if job == nil {
+			// Tunggu job baru, sinyal dari Enqueue, atau penghentian
+			timer := time.NewTimer(p.opts.PollInterval)
+			select {
+			case <-stopCtx.Done():
+				timer.Stop()
+				return
+			case <-p.wake:
+				timer.Stop()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		p.run(jobCtx, job)
+	}
+}
+
+// run menjalankan satu job dan mencatat hasil, kegagalan, atau pengembaliannya ke antrean
+func (p *Pool) run(ctx context.Context, job *model.Job) {
+	log.Printf("Running job %d (%s), attempt %d/%d", job.ID, job.Kind, job.Attempts, job.MaxAttempts)
+
+	// Status job tetap dicatat meskipun ctx dibatalkan saat shutdown
+	saveCtx := context.WithoutCancel(ctx)
+
+	// Perbarui updated_at secara berkala agar job panjang tidak dianggap ditinggalkan
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	defer stopHeartbeat()
+	go func() {
+		ticker := time.NewTicker(p.opts.StaleAfter / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				if err := p.db.TouchJob(heartbeatCtx, job.ID); err != nil && heartbeatCtx.Err() == nil {
+					log.Printf("Error updating job %d: %v", job.ID, err)
+				}
+			}
+		}
+	}()
+
+	progress := func(stage string, done, total int) {
+		if err := p.db.UpdateJobProgress(saveCtx, job.ID, stage, done, total); err != nil {
+			log.Printf("Error updating job %d progress: %v", job.ID, err)
+		}
+	}
+
+	result, err := p.handlers[job.Kind](ctx, job, progress)
+	stopHeartbeat()
+
+	switch {
+	case err == nil:
+		if err := p.db.CompleteJob(saveCtx, job.ID, result); err != nil {
+			log.Printf("Error completing job %d: %v", job.ID, err)
+			return
+		}
+		log.Printf("Job %d (%s) succeeded", job.ID, job.Kind)
+
+	case ctx.Err() != nil:
+		// Server dimatikan sebelum job selesai, jalankan ulang pada start berikutnya
+		if err := p.db.ReleaseJob(saveCtx, job.ID); err != nil {
+			log.Printf("Error releasing job %d: %v", job.ID, err)
+			return
+		}
+		log.Printf("Job %d (%s) interrupted by shutdown, returned to queue", job.ID, job.Kind)
+
+	default:
+		var retryAfter time.Duration
+		var permanent *permanentError
+		if !errors.As(err, &permanent) && job.Attempts < job.MaxAttempts {
+			retryAfter = p.retryDelay(job.Attempts)
+		}
+		if saveErr := p.db.FailJob(saveCtx, job.ID, err, retryAfter); saveErr != nil {
+			log.Printf("Error failing job %d: %v", job.ID, saveErr)
+			return
+		}
+		if retryAfter > 0 {
+			log.Printf("Job %d (%s) failed, retrying in %s: %v", job.ID, job.Kind, retryAfter, err)
+		} else {
+			log.Printf("Job %d (%s) failed: %v", job.ID, job.Kind, err)
+		}
+	}
+}
+
+// retryDelay menghitung jeda eksponensial dengan jitter sebelum percobaan berikutnya
+func (p *Pool) retryDelay(attempt int) time.Duration {
+	delay := p.opts.RetryBaseDelay << (attempt - 1)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
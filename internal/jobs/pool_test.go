@@ -0,0 +1,304 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"rag-chat-bot/internal/crawler"
+	"rag-chat-bot/internal/database"
+	"rag-chat-bot/internal/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeQueue adalah Queue di memori dengan aturan status yang sama dengan kueri PostgreSQL
+type fakeQueue struct {
+	mu     sync.Mutex
+	jobs   map[int]*model.Job
+	nextID int
+	// retries mencatat jeda setiap FailJob
+	retries []time.Duration
+}
+
+func newFakeQueue() *fakeQueue {
+	return &fakeQueue{jobs: make(map[int]*model.Job)}
+}
+
+func (q *fakeQueue) EnqueueJob(ctx context.Context, kind string, payload interface{}, maxAttempts int) (int, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	now := time.Now()
+	q.jobs[q.nextID] = &model.Job{ID: q.nextID, Kind: kind, Status: model.JobStatusPending, Payload: data,
+		MaxAttempts: maxAttempts, RunAfter: now, CreatedAt: now, UpdatedAt: now}
+	return q.nextID, nil
+}
+
+func (q *fakeQueue) ClaimJob(ctx context.Context, kinds []string, staleAfter time.Duration) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	for id := 1; id <= q.nextID; id++ {
+		job := q.jobs[id]
+		if job.Status != model.JobStatusPending || job.RunAfter.After(now) {
+			continue
+		}
+		job.Status = model.JobStatusRunning
+		job.Attempts++
+		job.Error = ""
+		job.UpdatedAt = now
+		claimed := *job
+		return &claimed, nil
+	}
+	return nil, nil
+}
+
+func (q *fakeQueue) UpdateJobProgress(ctx context.Context, jobID int, stage string, done, total int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.jobs[jobID]
+	job.Stage, job.ProgressDone, job.ProgressTotal = stage, done, total
+	return nil
+}
+
+func (q *fakeQueue) TouchJob(ctx context.Context, jobID int) error {
+	return nil
+}
+
+func (q *fakeQueue) CompleteJob(ctx context.Context, jobID int, result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.jobs[jobID]
+	job.Status, job.Result, job.Stage = model.JobStatusSucceeded, data, "done"
+	return nil
+}
+
+func (q *fakeQueue) FailJob(ctx context.Context, jobID int, jobErr error, retryAfter time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retries = append(q.retries, retryAfter)
+	job := q.jobs[jobID]
+	job.Error = jobErr.Error()
+	if retryAfter > 0 && job.Attempts < job.MaxAttempts {
+		job.Status = model.JobStatusPending
+		// Jeda tidak ditunggu agar test cepat; nilainya diperiksa melalui retries
+		job.RunAfter = time.Now()
+	} else {
+		job.Status = model.JobStatusFailed
+	}
+	return nil
+}
+
+func (q *fakeQueue) ReleaseJob(ctx context.Context, jobID int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.jobs[jobID]
+	if job.Status == model.JobStatusRunning {
+		job.Status = model.JobStatusPending
+		job.Attempts = max(job.Attempts-1, 0)
+	}
+	return nil
+}
+
+func (q *fakeQueue) GetJob(ctx context.Context, jobID int) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[jobID]
+	if !ok {
+		return nil, database.ErrJobNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+// waitForStatus menunggu sampai job berstatus salah satu dari statuses
+func waitForStatus(t *testing.T, pool *Pool, jobID int, statuses ...string) *model.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := pool.Get(context.Background(), jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, status := range statuses {
+			if job.Status == status {
+				return job
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("job %d did not reach status %v", jobID, statuses)
+	return nil
+}
+
+func testPool(queue Queue) *Pool {
+	return NewPool(queue, Options{
+		Workers:        2,
+		MaxAttempts:    3,
+		PollInterval:   time.Millisecond,
+		RetryBaseDelay: 100 * time.Millisecond,
+	})
+}
+
+func TestPoolRetries(t *testing.T) {
+	errTemporary := errors.New("temporary failure")
+
+	tests := []struct {
+		name         string
+		failures     int
+		err          error
+		wantStatus   string
+		wantAttempts int
+		wantRetries  int
+	}{
+		{"success", 0, nil, model.JobStatusSucceeded, 1, 0},
+		{"retried until success", 2, errTemporary, model.JobStatusSucceeded, 3, 2},
+		{"max attempts", 3, errTemporary, model.JobStatusFailed, 3, 2},
+		{"permanent error", 1, Permanent(errTemporary), model.JobStatusFailed, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := newFakeQueue()
+			pool := testPool(queue)
+			var calls atomic.Int32
+			pool.Register("test", func(ctx context.Context, job *model.Job, progress ProgressFunc) (interface{}, error) {
+				progress("working", 1, 2)
+				if int(calls.Add(1)) <= tt.failures {
+					return nil, tt.err
+				}
+				return map[string]int{"attempt": job.Attempts}, nil
+			})
+			pool.Start()
+			defer pool.Shutdown(context.Background())
+
+			jobID, err := pool.Enqueue(context.Background(), "test", map[string]string{"x": "y"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			job := waitForStatus(t, pool, jobID, model.JobStatusSucceeded, model.JobStatusFailed)
+
+			if job.Status != tt.wantStatus || job.Attempts != tt.wantAttempts {
+				t.Errorf("job status %s after %d attempts, want %s after %d", job.Status, job.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			if got := int(calls.Load()); got != tt.wantAttempts {
+				t.Errorf("handler called %d times, want %d", got, tt.wantAttempts)
+			}
+			if tt.wantStatus == model.JobStatusFailed && job.Error != errTemporary.Error() {
+				t.Errorf("job error = %q, want %q", job.Error, errTemporary)
+			}
+
+			queue.mu.Lock()
+			defer queue.mu.Unlock()
+			retried := 0
+			for i, delay := range queue.retries {
+				if delay == 0 {
+					continue
+				}
+				retried++
+				// Jeda berlipat ganda setiap percobaan dengan jitter hingga separuhnya
+				limit := pool.opts.RetryBaseDelay << i
+				if delay < limit/2 || delay > limit {
+					t.Errorf("retry %d delay = %s, want between %s and %s", i+1, delay, limit/2, limit)
+				}
+			}
+			if retried != tt.wantRetries {
+				t.Errorf("%d retries scheduled, want %d", retried, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestPoolEnqueueUnknownKind(t *testing.T) {
+	pool := testPool(newFakeQueue())
+	if _, err := pool.Enqueue(context.Background(), "unknown", nil); err == nil {
+		t.Error("Enqueue() succeeded for a kind without handler")
+	}
+}
+
+func TestPoolShutdown(t *testing.T) {
+	t.Run("waits for running jobs", func(t *testing.T) {
+		queue := newFakeQueue()
+		pool := testPool(queue)
+		started, release := make(chan struct{}), make(chan struct{})
+		pool.Register("test", func(ctx context.Context, job *model.Job, progress ProgressFunc) (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		pool.Start()
+		jobID, _ := pool.Enqueue(context.Background(), "test", nil)
+		<-started
+
+		shutdown := make(chan error)
+		go func() { shutdown <- pool.Shutdown(context.Background()) }()
+		select {
+		case err := <-shutdown:
+			t.Fatalf("Shutdown() returned %v before the job finished", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		if err := <-shutdown; err != nil {
+			t.Fatalf("Shutdown() error = %v", err)
+		}
+		if job, _ := pool.Get(context.Background(), jobID); job.Status != model.JobStatusSucceeded {
+			t.Errorf("job status = %s, want %s", job.Status, model.JobStatusSucceeded)
+		}
+	})
+
+	t.Run("releases jobs after timeout", func(t *testing.T) {
+		queue := newFakeQueue()
+		pool := testPool(queue)
+		started := make(chan struct{})
+		pool.Register("test", func(ctx context.Context, job *model.Job, progress ProgressFunc) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		pool.Start()
+		jobID, _ := pool.Enqueue(context.Background(), "test", nil)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Shutdown() error = %v, want deadline exceeded", err)
+		}
+		// Job yang terputus dikembalikan ke antrean tanpa menghabiskan percobaan
+		job, _ := pool.Get(context.Background(), jobID)
+		if job.Status != model.JobStatusPending || job.Attempts != 0 {
+			t.Errorf("job status %s with %d attempts, want pending with 0", job.Status, job.Attempts)
+		}
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		if err := testPool(newFakeQueue()).Shutdown(context.Background()); err != nil {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+	})
+}
+
+func TestHandlersRejectInvalidPayload(t *testing.T) {
+	handlers := map[string]Handler{
+		model.JobKindIngestDocument: IngestDocumentHandler(nil),
+		model.JobKindCrawlSite:      CrawlSiteHandler(nil, crawler.Options{}),
+	}
+	for kind, handler := range handlers {
+		_, err := handler(context.Background(), &model.Job{Kind: kind, Payload: json.RawMessage(`{"title": 1`)}, func(string, int, int) {})
+		var permanent *permanentError
+		if !errors.As(err, &permanent) {
+			t.Errorf("%s: error = %v, want permanent error", kind, err)
+		}
+	}
+}
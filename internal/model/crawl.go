@@ -0,0 +1,33 @@
+package model
+
+// CrawlRequest adalah permintaan untuk meng-crawl situs dokumentasi dan memasukkan
+// halamannya sebagai dokumen
+type CrawlRequest struct {
+	// URLs adalah URL awal; tautan di halaman HTML diikuti sampai MaxDepth
+	URLs []string `json:"urls,omitempty"`
+	// Sitemaps adalah URL sitemap.xml (atau sitemap index) yang semua URL-nya diunduh
+	Sitemaps []string `json:"sitemaps,omitempty"`
+	// MaxDepth, MaxPages dan AllowedDomains menimpa konfigurasi crawler jika diisi
+	MaxDepth       *int     `json:"max_depth,omitempty"`
+	MaxPages       int      `json:"max_pages,omitempty"`
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+	// Metadata ditambahkan ke setiap dokumen hasil crawling
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CrawlResult adalah hasil job crawl_site
+type CrawlResult struct {
+	// Discovered adalah jumlah URL yang dijadwalkan untuk diunduh
+	Discovered int `json:"discovered"`
+	// Created, Updated dan Unchanged menghitung halaman yang diunduh menurut perubahan dokumennya
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	// Duplicates adalah halaman yang kontennya sama dengan dokumen lain sehingga tidak disimpan
+	Duplicates int `json:"duplicates"`
+	// NotModified adalah halaman yang dilewati karena server menjawab 304 Not Modified
+	NotModified int `json:"not_modified"`
+	// Skipped adalah halaman yang dilarang robots.txt atau tipe kontennya tidak didukung
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
@@ -7,17 +7,110 @@ import (
 
 // Document merepresentasikan dokumen sumber untuk sistem RAG
 type Document struct {
-	ID        int                    `json:"id"`
-	Title     string                 `json:"title"`
-	Content   string                 `json:"content"`
-	Metadata  map[string]interface{} `json:"metadata"`
-	CreatedAt time.Time              `json:"created_at"`
+	ID       int                    `json:"id"`
+	Title    string                 `json:"title"`
+	Content  string                 `json:"content"`
+	Metadata map[string]interface{} `json:"metadata"`
+	// ContentHash adalah hash konten yang spasinya dinormalisasi, untuk mendeteksi duplikat
+	ContentHash string `json:"content_hash,omitempty"`
+	// Version bertambah setiap kali konten yang sama disimpan sebagai versi baru; versi lama
+	// menyimpan SupersededAt dan tidak lagi muncul di pencarian
+	Version           int        `json:"version"`
+	PreviousVersionID *int       `json:"previous_version_id,omitempty"`
+	SupersededAt      *time.Time `json:"superseded_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
-// DocumentWithScore merepresentasikan dokumen dengan skor kesamaan
+// DocumentSummary adalah ringkasan dokumen tanpa konten untuk daftar dokumen
+type DocumentSummary struct {
+	ID         int                    `json:"id"`
+	Title      string                 `json:"title"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Version    int                    `json:"version"`
+	ChunkCount int                    `json:"chunk_count"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// DocumentChunk merepresentasikan potongan dokumen yang di-embed secara terpisah
+type DocumentChunk struct {
+	ID         int    `json:"id"`
+	DocumentID int    `json:"document_id"`
+	ChunkIndex int    `json:"chunk_index"`
+	Content    string `json:"content"`
+	// StartOffset dan EndOffset adalah posisi byte potongan di konten dokumen
+	StartOffset int                    `json:"start_offset"`
+	EndOffset   int                    `json:"end_offset"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	ContentHash string                 `json:"content_hash,omitempty"`
+	Embedding   []float32              `json:"-"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// DocumentWithScore merepresentasikan dokumen dengan skor kesamaan.
+// Hasil pencarian berbasis potongan menyertakan Chunk yang cocok; konten lengkap dokumen
+// induk tidak dimuat sehingga Content dapat kosong.
 type DocumentWithScore struct {
 	Document
+	Chunk *DocumentChunk `json:"chunk,omitempty"`
+	// Score adalah skor akhir yang dipakai untuk mengurutkan hasil (0-1)
 	Score float64 `json:"score"`
+	// VectorScore adalah cosine similarity terhadap embedding kueri
+	VectorScore float64 `json:"vector_score"`
+	// KeywordScore adalah peringkat full-text search, nol jika tidak ada kata kunci yang cocok
+	KeywordScore float64 `json:"keyword_score"`
+}
+
+// Section mengembalikan jalur heading potongan, misalnya "Install > Linux > Proxy"
+func (d *DocumentWithScore) Section() string {
+	if d.Chunk == nil {
+		return ""
+	}
+	section, _ := d.Chunk.Metadata["section_path"].(string)
+	return section
+}
+
+// SourceURL mengembalikan alamat asal dokumen dari metadata source_url (hasil crawling) atau url
+func (d *DocumentWithScore) SourceURL() string {
+	if url, ok := d.Metadata["source_url"].(string); ok {
+		return url
+	}
+	url, _ := d.Metadata["url"].(string)
+	return url
+}
+
+// Symbol mengembalikan nama simbol kode sumber yang dicakup potongan beserta paketnya
+// (misalnya "rag.Processor.ProcessDocument"), dan rentang barisnya
+func (d *DocumentWithScore) Symbol() (symbol string, startLine, endLine int) {
+	if d.Chunk == nil {
+		return "", 0, 0
+	}
+	symbol, _ = d.Chunk.Metadata["symbol"].(string)
+	if pkg, ok := d.Chunk.Metadata["package"].(string); ok && symbol != "" && symbol != pkg {
+		symbol = pkg + "." + symbol
+	}
+	return symbol, metadataInt(d.Chunk.Metadata, "start_line"), metadataInt(d.Chunk.Metadata, "end_line")
+}
+
+// metadataInt membaca nilai bilangan bulat dari metadata, baik sebagai int maupun angka JSON
+func metadataInt(metadata map[string]interface{}, key string) int {
+	switch v := metadata[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// Text mengembalikan teks yang cocok dengan kueri: konten potongan jika ada, atau konten dokumen
+func (d *DocumentWithScore) Text() string {
+	if d.Chunk != nil {
+		return d.Chunk.Content
+	}
+	return d.Content
 }
 
 // ToJSON mengkonversi Document ke JSON string
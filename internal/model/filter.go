@@ -0,0 +1,110 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MetadataFilter adalah ekspresi filter terhadap metadata dokumen. Setiap kunci adalah nama
+// field metadata, dan nilainya berupa nilai yang harus sama persis atau objek operator, misalnya:
+//
+//	{"category": "faq", "tags": {"$in": ["billing", "invoice"]},
+//	 "published_at": {"$gte": "2024-01-01"}, "draft": {"$exists": false}}
+//
+// Semua kondisi harus terpenuhi (AND). Nilai array atau objek pada kesamaan ($eq, $ne) dicocokkan
+// dengan containment JSONB: {"tags": ["billing"]} cocok dengan dokumen yang tags-nya memuat
+// "billing" dan mungkin nilai lain, bukan hanya yang tags-nya persis ["billing"]. $in cocok jika
+// field sama dengan salah satu nilai, atau jika field berupa array, memuat salah satu nilai.
+type MetadataFilter map[string]interface{}
+
+// Operator filter metadata yang didukung
+const (
+	FilterEq     = "$eq"
+	FilterNe     = "$ne"
+	FilterIn     = "$in"
+	FilterGt     = "$gt"
+	FilterGte    = "$gte"
+	FilterLt     = "$lt"
+	FilterLte    = "$lte"
+	FilterExists = "$exists"
+)
+
+// FilterCondition adalah satu kondisi filter pada satu field metadata
+type FilterCondition struct {
+	Field    string
+	Operator string
+	Value    interface{}
+}
+
+// Conditions menguraikan filter menjadi daftar kondisi dengan urutan field yang stabil
+func (f MetadataFilter) Conditions() ([]FilterCondition, error) {
+	fields := make([]string, 0, len(f))
+	for field := range f {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var conditions []FilterCondition
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("filter field name cannot be empty")
+		}
+
+		operators, ok := f[field].(map[string]interface{})
+		if !ok {
+			// Nilai langsung berarti kesamaan
+			conditions = append(conditions, FilterCondition{Field: field, Operator: FilterEq, Value: f[field]})
+			continue
+		}
+
+		names := make([]string, 0, len(operators))
+		for name := range operators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			condition := FilterCondition{Field: field, Operator: name, Value: operators[name]}
+			if err := condition.validate(); err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+
+	return conditions, nil
+}
+
+// Validate memeriksa apakah filter hanya menggunakan operator dan nilai yang didukung
+func (f MetadataFilter) Validate() error {
+	_, err := f.Conditions()
+	return err
+}
+
+// validate memeriksa operator dan tipe nilai kondisi
+func (c FilterCondition) validate() error {
+	switch c.Operator {
+	case FilterEq, FilterNe:
+		return nil
+	case FilterIn:
+		if _, ok := c.Value.([]interface{}); !ok {
+			return fmt.Errorf("filter %s on %q requires an array", c.Operator, c.Field)
+		}
+		return nil
+	case FilterGt, FilterGte, FilterLt, FilterLte:
+		// Rentang berlaku untuk angka dan string (misalnya tanggal ISO 8601)
+		switch c.Value.(type) {
+		case float64, string:
+			return nil
+		default:
+			return fmt.Errorf("filter %s on %q requires a number or a date string", c.Operator, c.Field)
+		}
+	case FilterExists:
+		if _, ok := c.Value.(bool); !ok {
+			return fmt.Errorf("filter %s on %q requires a boolean", c.Operator, c.Field)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported filter operator %q on %q", c.Operator, c.Field)
+	}
+}
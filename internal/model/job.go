@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status job di antrean
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Jenis job yang diproses worker
+const (
+	JobKindIngestDocument = "ingest_document"
+	JobKindCrawlSite      = "crawl_site"
+)
+
+// Job merepresentasikan pekerjaan latar belakang di antrean ingestion_jobs
+type Job struct {
+	ID     int    `json:"id"`
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	// Payload adalah masukan job, misalnya CreateDocumentRequest untuk ingest_document
+	Payload json.RawMessage `json:"-"`
+	// Result adalah keluaran job yang berhasil, misalnya {"doc_id": 1}
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	// Stage, ProgressDone dan ProgressTotal melaporkan kemajuan job yang sedang berjalan
+	Stage         string     `json:"stage,omitempty"`
+	ProgressDone  int        `json:"progress_done"`
+	ProgressTotal int        `json:"progress_total"`
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"max_attempts"`
+	RunAfter      time.Time  `json:"run_after"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobAcceptedResponse adalah respons saat permintaan diterima sebagai job latar belakang
+type JobAcceptedResponse struct {
+	Success bool   `json:"success"`
+	JobID   int    `json:"job_id"`
+	Status  string `json:"status"`
+	// StatusURL adalah endpoint untuk memantau kemajuan job
+	StatusURL string `json:"status_url"`
+}
@@ -23,6 +23,15 @@ type ChatMessage struct {
 type ChatRequest struct {
 	SessionID string `json:"session_id"`
 	Message   string `json:"message"`
+	// Stream mengaktifkan respons bertahap melalui Server-Sent Events
+	Stream bool `json:"stream,omitempty"`
+	// Filter membatasi dokumen konteks berdasarkan metadata, lihat MetadataFilter
+	Filter MetadataFilter `json:"filter,omitempty"`
+	// Language memilih bahasa template prompt, misalnya "id" atau "en". Jika kosong, bahasa
+	// diambil dari header Accept-Language atau PROMPT_LANGUAGE.
+	Language string `json:"language,omitempty"`
+	// Collection memilih template prompt khusus koleksi jika tersedia
+	Collection string `json:"collection,omitempty"`
 }
 
 // ChatResponse adalah struktur respons chat
@@ -30,17 +39,163 @@ type ChatResponse struct {
 	Success   bool   `json:"success"`
 	Message   string `json:"message"`
 	SessionID string `json:"session_id"`
+	// Sources adalah dokumen konteks jawaban; penanda [n] di Message merujuk ke Source.Index
+	Sources []Source `json:"sources"`
+	// AnswerSource menjelaskan asal jawaban: "documents", "conversation", "general" atau "refused"
+	AnswerSource string `json:"answer_source,omitempty"`
+	// Usage adalah jumlah token yang dipakai model chat, kosong jika model tidak dipanggil atau
+	// tidak melaporkannya
+	Usage *Usage `json:"usage,omitempty"`
 }
 
+// Usage adalah jumlah token prompt dan jawaban dari model chat
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Asal jawaban chat. Selain AnswerFromDocuments, jawaban dihasilkan tanpa dokumen yang relevan
+// sesuai NO_ANSWER_MODE.
+const (
+	AnswerFromDocuments    = "documents"
+	AnswerFromConversation = "conversation"
+	AnswerFromGeneral      = "general"
+	AnswerRefused          = "refused"
+)
+
+// Source adalah dokumen yang digunakan sebagai konteks saat menghasilkan jawaban
+type Source struct {
+	// Index adalah nomor sumber yang dipakai model di penanda sitasi [n], dimulai dari 1
+	Index      int     `json:"index"`
+	DocumentID int     `json:"document_id"`
+	Title      string  `json:"title"`
+	Score      float64 `json:"score"`
+	// ChunkID, ChunkIndex dan Excerpt menunjuk ke potongan dokumen yang menjadi konteks
+	ChunkID    int    `json:"chunk_id,omitempty"`
+	ChunkIndex int    `json:"chunk_index"`
+	Excerpt    string `json:"excerpt,omitempty"`
+	// URL adalah alamat asal dokumen dari metadata source_url atau url, jika ada
+	URL      string                 `json:"url,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Cited bernilai true jika jawaban merujuk sumber ini dengan penanda [n]
+	Cited bool `json:"cited"`
+	// Section dan Anchor menunjuk ke bagian dokumen Markdown, misalnya "Install > Linux"
+	Section string `json:"section,omitempty"`
+	Anchor  string `json:"anchor,omitempty"`
+	// Symbol, StartLine dan EndLine menunjuk ke deklarasi di kode sumber
+	Symbol    string `json:"symbol,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// Jenis event Server-Sent Events yang dikirim saat chat streaming
+const (
+	StreamEventToken   = "token"
+	StreamEventSources = "sources"
+	StreamEventDone    = "done"
+	StreamEventError   = "error"
+)
+
+// Kebijakan saat dokumen dengan konten yang sama sudah ada
+const (
+	// DuplicateSkip tidak menyimpan apa pun dan mengembalikan dokumen yang sudah ada
+	DuplicateSkip = "skip"
+	// DuplicateReplace mengganti judul dan metadata dokumen yang sudah ada
+	DuplicateReplace = "replace"
+	// DuplicateNewVersion menyimpan dokumen sebagai versi baru dan menyembunyikan versi lama dari pencarian
+	DuplicateNewVersion = "new-version"
+)
+
+// Hasil penyimpanan dokumen baru
+const (
+	IngestCreated    = "created"
+	IngestSkipped    = "skipped"
+	IngestReplaced   = "replaced"
+	IngestNewVersion = "new_version"
+)
+
 // CreateDocumentRequest adalah struktur permintaan untuk membuat dokumen baru
 type CreateDocumentRequest struct {
 	Title    string                 `json:"title"`
 	Content  string                 `json:"content"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// OnDuplicate adalah kebijakan jika konten yang sama sudah ada: "skip" (default),
+	// "replace" atau "new-version"
+	OnDuplicate string `json:"on_duplicate,omitempty"`
+}
+
+// ValidDuplicatePolicy memeriksa apakah policy adalah kebijakan duplikat yang dikenal
+func ValidDuplicatePolicy(policy string) bool {
+	switch policy {
+	case "", DuplicateSkip, DuplicateReplace, DuplicateNewVersion:
+		return true
+	default:
+		return false
+	}
+}
+
+// IngestResult adalah hasil penyimpanan dokumen baru
+type IngestResult struct {
+	DocID int `json:"doc_id"`
+	// Action adalah "created", "skipped", "replaced" atau "new_version"
+	Action string `json:"action"`
+	// DuplicateOf adalah ID dokumen yang kontennya sama, jika ada
+	DuplicateOf int `json:"duplicate_of,omitempty"`
+	Version     int `json:"version"`
 }
 
 // CreateDocumentResponse adalah struktur respons saat membuat dokumen baru
 type CreateDocumentResponse struct {
 	Success bool `json:"success"`
 	DocID   int  `json:"doc_id"`
+	// Action, DuplicateOf dan Version menjelaskan apa yang terjadi jika konten yang sama sudah ada
+	Action      string `json:"action"`
+	DuplicateOf int    `json:"duplicate_of,omitempty"`
+	Version     int    `json:"version"`
+}
+
+// UpdateDocumentRequest adalah struktur permintaan untuk memperbarui dokumen. Field yang
+// bernilai nil tidak diubah; Metadata yang diberikan menggantikan metadata lama seluruhnya.
+type UpdateDocumentRequest struct {
+	Title    *string                `json:"title,omitempty"`
+	Content  *string                `json:"content,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// UpdateDocumentResponse adalah struktur respons saat memperbarui dokumen
+type UpdateDocumentResponse struct {
+	Success  bool      `json:"success"`
+	Document *Document `json:"document"`
+	// Reembedded bernilai true jika dokumen dipecah dan di-embed ulang
+	Reembedded bool `json:"reembedded"`
+}
+
+// ListDocumentsResponse adalah struktur respons daftar dokumen
+type ListDocumentsResponse struct {
+	Success   bool               `json:"success"`
+	Documents []*DocumentSummary `json:"documents"`
+	Total     int                `json:"total"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+}
+
+// UploadedDocument adalah hasil unggahan satu file
+type UploadedDocument struct {
+	Filename  string `json:"filename"`
+	Title     string `json:"title"`
+	MIMEType  string `json:"mime_type"`
+	SizeBytes int64  `json:"size_bytes"`
+	// DocID diisi saat dokumen diproses langsung (?wait=true), JobID dan StatusURL saat diproses di latar belakang
+	DocID       int    `json:"doc_id,omitempty"`
+	Action      string `json:"action,omitempty"`
+	DuplicateOf int    `json:"duplicate_of,omitempty"`
+	JobID       int    `json:"job_id,omitempty"`
+	StatusURL   string `json:"status_url,omitempty"`
+}
+
+// UploadDocumentsResponse adalah struktur respons unggahan file dokumen
+type UploadDocumentsResponse struct {
+	Success   bool                `json:"success"`
+	Documents []*UploadedDocument `json:"documents"`
 }
@@ -0,0 +1,41 @@
+package model
+
+// SearchRequest adalah struktur permintaan pencarian dokumen tanpa menghasilkan jawaban
+type SearchRequest struct {
+	Query string `json:"query"`
+	// TopK adalah jumlah hasil maksimum (default sama dengan chat, maksimum 50)
+	TopK int `json:"top_k,omitempty"`
+	// MinScore membuang hasil yang skornya di bawah nilai ini (0-1)
+	MinScore float64 `json:"min_score,omitempty"`
+	// Filter membatasi hasil berdasarkan metadata dokumen, lihat MetadataFilter
+	Filter MetadataFilter `json:"filter,omitempty"`
+	// Mode adalah "vector", "keyword" atau "hybrid"; default sesuai RETRIEVAL_MODE
+	Mode string `json:"mode,omitempty"`
+}
+
+// SearchResult adalah satu potongan dokumen hasil pencarian
+type SearchResult struct {
+	DocumentID int    `json:"document_id"`
+	Title      string `json:"title"`
+	// Score adalah skor yang dipakai untuk mengurutkan hasil; VectorScore dan KeywordScore
+	// adalah skor dari pencarian vektor dan full-text search
+	Score        float64 `json:"score"`
+	VectorScore  float64 `json:"vector_score"`
+	KeywordScore float64 `json:"keyword_score"`
+	ChunkID      int     `json:"chunk_id,omitempty"`
+	ChunkIndex   int     `json:"chunk_index"`
+	Section      string  `json:"section,omitempty"`
+	URL          string  `json:"url,omitempty"`
+	// Snippet adalah cuplikan konten dengan kata kueri dibungkus <mark>...</mark> (HTML-escaped)
+	Snippet  string                 `json:"snippet"`
+	Content  string                 `json:"content"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// SearchResponse adalah struktur respons pencarian dokumen
+type SearchResponse struct {
+	Success bool           `json:"success"`
+	Query   string         `json:"query"`
+	Mode    string         `json:"mode"`
+	Results []SearchResult `json:"results"`
+}
@@ -0,0 +1,324 @@
+// Package prompt memuat template prompt model chat dan pesan fallback untuk setiap bahasa.
+//
+// Template ditulis dengan text/template dan disusun per bahasa:
+//
+//	<bahasa>/<nama>.tmpl
+//	collections/<koleksi>/<bahasa>/<nama>.tmpl
+//
+// Template bawaan di-embed ke dalam binary dan dapat ditimpa dengan file di direktori yang sama
+// strukturnya. Template koleksi hanya perlu memuat template yang berbeda dari template bahasanya.
+package prompt
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"rag-chat-bot/internal/model"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var embedded embed.FS
+
+// Nama template yang dikenali
+const (
+	// System adalah system prompt saat jawaban berasal dari dokumen
+	System = "system"
+	// Context adalah pesan pengguna yang memuat konteks dokumen dan pertanyaan
+	Context = "context"
+	// ContextDocument adalah satu entri dokumen di dalam Context, dieksekusi dengan DocumentData
+	ContextDocument = "context_document"
+	// NoAnswerConversation dan NoAnswerGeneral adalah system prompt saat tidak ada dokumen
+	// yang relevan pada NO_ANSWER_MODE conversation dan general
+	NoAnswerConversation = "no_answer_conversation"
+	NoAnswerGeneral      = "no_answer_general"
+	// NoAnswer adalah jawaban saat pertanyaan tidak dapat dijawab
+	NoAnswer = "no_answer"
+	// Error adalah jawaban saat respons tidak dapat dihasilkan
+	Error = "error"
+	// SummarizeHistory adalah permintaan ke model untuk meringkas History yang tidak muat di
+	// context window, dan HistorySummary adalah pesan yang membawa ringkasannya (Summary)
+	SummarizeHistory = "summarize_history"
+	HistorySummary   = "history_summary"
+)
+
+// names adalah semua template yang harus tersedia untuk bahasa default
+var names = []string{System, Context, ContextDocument, NoAnswerConversation, NoAnswerGeneral, NoAnswer, Error, SummarizeHistory, HistorySummary}
+
+// collectionsDir adalah direktori template per koleksi
+const collectionsDir = "collections"
+
+// Data adalah variabel yang tersedia untuk template selain ContextDocument
+type Data struct {
+	// Query adalah pertanyaan pengguna saat ini
+	Query string
+	// Context adalah entri dokumen yang sudah dirender dengan ContextDocument
+	Context string
+	// History adalah percakapan sebelumnya, tanpa pertanyaan saat ini
+	History []model.ChatMessage
+	// Date adalah tanggal hari ini dalam format YYYY-MM-DD
+	Date string
+	// Language dan Collection adalah pilihan template yang sedang dipakai
+	Language   string
+	Collection string
+	// NoAnswerMessage adalah jawaban saat pertanyaan tidak dapat dijawab
+	NoAnswerMessage string
+	// Summary adalah ringkasan percakapan untuk template HistorySummary
+	Summary string
+}
+
+// DocumentData adalah variabel untuk template ContextDocument
+type DocumentData struct {
+	// Index adalah nomor dokumen untuk penanda sitasi [n], dimulai dari 1
+	Index int
+	Title string
+	// Relevance adalah skor relevansi dalam persen
+	Relevance float64
+	Section   string
+	// Symbol, StartLine dan EndLine diisi untuk potongan kode sumber
+	Symbol    string
+	StartLine int
+	EndLine   int
+	Content   string
+}
+
+// Selector memilih template berdasarkan bahasa dan koleksi. Nilai kosong berarti default.
+type Selector struct {
+	Language   string
+	Collection string
+}
+
+// Templates adalah kumpulan template prompt yang sudah divalidasi
+type Templates struct {
+	defaultLanguage string
+	// sets berisi template per bahasa dengan kunci "<bahasa>" atau "<koleksi>/<bahasa>"
+	sets        map[string]map[string]*template.Template
+	languages   map[string]bool
+	collections map[string]bool
+}
+
+// Load memuat template bawaan lalu menimpanya dengan template dari dir jika dir diisi.
+// Semua template dieksekusi dengan data contoh agar kesalahan terdeteksi saat startup.
+func Load(dir, defaultLanguage string) (*Templates, error) {
+	var overrides fs.FS
+	if dir != "" {
+		overrides = os.DirFS(dir)
+	}
+
+	t, err := loadFS(overrides, defaultLanguage)
+	if err != nil && dir != "" {
+		return nil, fmt.Errorf("error loading templates from %s: %w", dir, err)
+	}
+	return t, err
+}
+
+// loadFS memuat template bawaan lalu menimpanya dengan template di overrides jika tidak nil
+func loadFS(overrides fs.FS, defaultLanguage string) (*Templates, error) {
+	t := &Templates{
+		defaultLanguage: NormalizeLanguage(defaultLanguage),
+		sets:            make(map[string]map[string]*template.Template),
+		languages:       make(map[string]bool),
+		collections:     make(map[string]bool),
+	}
+
+	builtin, err := fs.Sub(embedded, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("error opening embedded templates: %w", err)
+	}
+	if err := t.load(builtin); err != nil {
+		return nil, err
+	}
+	if overrides != nil {
+		if err := t.load(overrides); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Default mengembalikan template bawaan dengan bahasa default Indonesia
+func Default() *Templates {
+	t, err := Load("", "id")
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// load mem-parsing semua file .tmpl di fsys
+func (t *Templates) load(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".tmpl" {
+			return nil
+		}
+
+		key, collection, language, ok := splitTemplatePath(p)
+		if !ok {
+			return fmt.Errorf("unexpected template path %s", p)
+		}
+		name := strings.TrimSuffix(path.Base(p), ".tmpl")
+		if !knownName(name) {
+			return fmt.Errorf("unknown template %s", p)
+		}
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("error reading template %s: %w", p, err)
+		}
+		tmpl, err := template.New(p).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("error parsing template: %w", err)
+		}
+
+		if t.sets[key] == nil {
+			t.sets[key] = make(map[string]*template.Template)
+		}
+		t.sets[key][name] = tmpl
+		t.languages[language] = true
+		if collection != "" {
+			t.collections[collection] = true
+		}
+		return nil
+	})
+}
+
+// splitTemplatePath memecah path template menjadi kunci set, koleksi dan bahasa
+func splitTemplatePath(p string) (key, collection, language string, ok bool) {
+	parts := strings.Split(p, "/")
+	switch {
+	case len(parts) == 2 && parts[0] != collectionsDir:
+		language = NormalizeLanguage(parts[0])
+		return language, "", language, language != ""
+	case len(parts) == 4 && parts[0] == collectionsDir:
+		collection = parts[1]
+		language = NormalizeLanguage(parts[2])
+		return collection + "/" + language, collection, language, collection != "" && language != ""
+	}
+	return "", "", "", false
+}
+
+// knownName memeriksa apakah name adalah nama template yang dikenali
+func knownName(name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validate memastikan bahasa default lengkap dan semua template dapat dieksekusi
+func (t *Templates) validate() error {
+	defaults, ok := t.sets[t.defaultLanguage]
+	if !ok {
+		return fmt.Errorf("no templates for default language %q", t.defaultLanguage)
+	}
+	for _, name := range names {
+		if defaults[name] == nil {
+			return fmt.Errorf("missing template %s/%s.tmpl", t.defaultLanguage, name)
+		}
+	}
+
+	sample := Data{
+		Query:           "Apa itu RAG?",
+		Context:         "[1] Contoh dokumen",
+		History:         []model.ChatMessage{{Role: "user", Content: "Halo"}, {Role: "assistant", Content: "Halo!"}},
+		Date:            "2006-01-02",
+		Language:        t.defaultLanguage,
+		NoAnswerMessage: "Tidak ada jawaban.",
+		Summary:         "Pengguna menyapa asisten.",
+	}
+	sampleDocument := DocumentData{
+		Index:     1,
+		Title:     "Contoh",
+		Relevance: 87.5,
+		Section:   "Pendahuluan",
+		Symbol:    "main",
+		StartLine: 1,
+		EndLine:   10,
+		Content:   "Isi dokumen",
+	}
+
+	keys := make([]string, 0, len(t.sets))
+	for key := range t.sets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for name, tmpl := range t.sets[key] {
+			var data interface{} = sample
+			if name == ContextDocument {
+				data = sampleDocument
+			}
+			if err := tmpl.Execute(&bytes.Buffer{}, data); err != nil {
+				return fmt.Errorf("error executing template: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// NormalizeLanguage mengubah tag bahasa seperti "en-US" menjadi subtag utamanya ("en")
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
+
+// DefaultLanguage mengembalikan bahasa yang dipakai saat permintaan tidak memilih bahasa
+func (t *Templates) DefaultLanguage() string {
+	return t.defaultLanguage
+}
+
+// HasLanguage memeriksa apakah ada template untuk language
+func (t *Templates) HasLanguage(language string) bool {
+	return t.languages[NormalizeLanguage(language)]
+}
+
+// HasCollection memeriksa apakah ada template untuk collection
+func (t *Templates) HasCollection(collection string) bool {
+	return t.collections[collection]
+}
+
+// Render mengeksekusi template name untuk sel. Template dicari berurutan di koleksi dengan
+// bahasa yang dipilih, koleksi dengan bahasa default, bahasa yang dipilih, lalu bahasa default.
+// Spasi di awal dan akhir hasil dibuang.
+func (t *Templates) Render(name string, sel Selector, data interface{}) (string, error) {
+	language := NormalizeLanguage(sel.Language)
+	if language == "" {
+		language = t.defaultLanguage
+	}
+
+	var keys []string
+	if sel.Collection != "" {
+		keys = append(keys, sel.Collection+"/"+language, sel.Collection+"/"+t.defaultLanguage)
+	}
+	keys = append(keys, language, t.defaultLanguage)
+
+	for _, key := range keys {
+		tmpl := t.sets[key][name]
+		if tmpl == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("error executing template: %w", err)
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+	return "", fmt.Errorf("template %s not found", name)
+}
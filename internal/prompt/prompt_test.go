@@ -0,0 +1,131 @@
+package prompt
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// file membuat file template untuk fstest.MapFS
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func TestRenderFallback(t *testing.T) {
+	overrides := fstest.MapFS{
+		"id/no_answer.tmpl":                     file("Maaf, belum ada jawaban untuk {{.Query}}."),
+		"fr/system.tmpl":                        file("Système FR"),
+		"collections/billing/en/system.tmpl":    file("Billing EN system"),
+		"collections/billing/id/system.tmpl":    file("Sistem billing ID"),
+		"collections/billing/id/no_answer.tmpl": file("Hubungi tim billing."),
+		"README.md":                             file("File selain .tmpl diabaikan"),
+	}
+	templates, err := loadFS(overrides, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtin, err := loadFS(nil, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtinError := func(language string) string {
+		out, err := builtin.Render(Error, Selector{Language: language}, Data{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		sel      Selector
+		want     string
+	}{
+		{"override replaces builtin", NoAnswer, Selector{}, "Maaf, belum ada jawaban untuk Apa itu RAG?."},
+		{"collection and language", System, Selector{Language: "en", Collection: "billing"}, "Billing EN system"},
+		{"collection with default language", NoAnswer, Selector{Language: "en", Collection: "billing"}, "Hubungi tim billing."},
+		{"language without collection template", Error, Selector{Language: "en", Collection: "billing"}, builtinError("en")},
+		{"new language", System, Selector{Language: "fr"}, "Système FR"},
+		{"new language falls back to default", Error, Selector{Language: "fr"}, builtinError("id")},
+		{"language tag is normalized", System, Selector{Language: "fr-CA"}, "Système FR"},
+		{"unknown language", Error, Selector{Language: "de"}, builtinError("id")},
+		{"unknown collection", Error, Selector{Collection: "sales"}, builtinError("id")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templates.Render(tt.template, tt.sel, Data{Query: "Apa itu RAG?"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Render(%s, %+v) = %q, want %q", tt.template, tt.sel, got, tt.want)
+			}
+		})
+	}
+
+	if !templates.HasLanguage("FR") || templates.HasLanguage("de") {
+		t.Errorf("HasLanguage() does not match the loaded languages")
+	}
+	if !templates.HasCollection("billing") || templates.HasCollection("sales") {
+		t.Errorf("HasCollection() does not match the loaded collections")
+	}
+	if _, err := templates.Render("unknown", Selector{}, Data{}); err == nil {
+		t.Errorf("Render() of an unknown template succeeded")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name            string
+		overrides       fstest.MapFS
+		defaultLanguage string
+		wantErr         string
+	}{
+		{"default language without templates", nil, "de", `no templates for default language "de"`},
+		{"incomplete default language", fstest.MapFS{"fr/system.tmpl": file("Système")}, "fr", "missing template fr/context.tmpl"},
+		{"parse error", fstest.MapFS{"id/system.tmpl": file("{{.Query")}, "id", "error parsing template"},
+		{"unknown field", fstest.MapFS{"en/system.tmpl": file("{{.Unknown}}")}, "id", "error executing template"},
+		{"unknown field in collection", fstest.MapFS{"collections/billing/id/context_document.tmpl": file("{{.Query}}")}, "id", "error executing template"},
+		{"unknown template name", fstest.MapFS{"id/greeting.tmpl": file("Halo")}, "id", "unknown template id/greeting.tmpl"},
+		{"unexpected path", fstest.MapFS{"id/extra/system.tmpl": file("Halo")}, "id", "unexpected template path id/extra/system.tmpl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var overrides fs.FS
+			if tt.overrides != nil {
+				overrides = tt.overrides
+			}
+			_, err := loadFS(overrides, tt.defaultLanguage)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadFS() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	if _, err := Load("", "en-US"); err != nil {
+		t.Errorf("Load() with builtin templates error = %v", err)
+	}
+	if _, err := Load(t.TempDir()+"/missing", "id"); err == nil || !strings.Contains(err.Error(), "error loading templates from") {
+		t.Errorf("Load() of a missing directory error = %v", err)
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"id":     "id",
+		"en-US":  "en",
+		" EN_gb": "en",
+		"":       "",
+	}
+	for language, want := range tests {
+		if got := NormalizeLanguage(language); got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", language, got, want)
+		}
+	}
+}
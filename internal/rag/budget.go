@@ -0,0 +1,318 @@
+package rag
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log"
+	"rag-chat-bot/internal/embedding"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/prompt"
+	"rag-chat-bot/internal/tokenizer"
+	"strings"
+	"sync"
+)
+
+// Cara memangkas riwayat percakapan yang tidak muat di context window model
+const (
+	// HistoryTruncate membuang pesan terlama
+	HistoryTruncate = "truncate"
+	// HistorySummarize mengganti pesan terlama dengan ringkasan dari model chat
+	HistorySummarize = "summarize"
+)
+
+const (
+	// messageOverheadTokens adalah perkiraan token tambahan untuk role dan pemisah setiap pesan
+	messageOverheadTokens = 4
+	// replyPrimingTokens adalah token yang ditambahkan model sebelum jawaban
+	replyPrimingTokens = 3
+	// minTruncatedTokens adalah ukuran minimum konten dokumen yang dipotong agar muat; dokumen
+	// yang hanya muat lebih pendek dari ini dibuang
+	minTruncatedTokens = 64
+	// maxSummaryTokens membatasi panjang ringkasan riwayat percakapan
+	maxSummaryTokens = 300
+)
+
+// truncatedMarker menandai konten dokumen yang dipotong agar muat di context window
+const truncatedMarker = " …"
+
+// ErrContextWindowExceeded dikembalikan saat pertanyaan dan prompt wajib tidak muat di context
+// window model meskipun tanpa dokumen dan riwayat
+var ErrContextWindowExceeded = errors.New("prompt exceeds the model context window")
+
+// promptBudget mengembalikan jumlah token yang tersedia untuk semua pesan prompt, yaitu context
+// window model dikurangi token yang dicadangkan untuk jawaban
+func (r *Retriever) promptBudget() int {
+	return r.chatAPI.ContextWindow() - r.chatAPI.MaxOutputTokens() - replyPrimingTokens
+}
+
+// messageTokens menghitung token satu pesan chat beserta overhead-nya
+func (r *Retriever) messageTokens(content string) int {
+	return r.opts.Tokens.Count(content) + messageOverheadTokens
+}
+
+// fitDocuments merender entri konteks untuk docs, yang sudah terurut dari skor tertinggi, sampai
+// maxTokens habis. Dokumen yang tidak muat utuh dipotong jika sisa anggaran cukup, dan dokumen
+// berskor lebih rendah dibuang. Dokumen yang masuk ke konteks dikembalikan bersama teksnya.
+func (r *Retriever) fitDocuments(docs []*model.DocumentWithScore, maxTokens int, sel prompt.Selector) (string, []*model.DocumentWithScore, error) {
+	var contextBuilder strings.Builder
+	usedTokens := 0
+
+	for i, doc := range docs {
+		documentData := prompt.DocumentData{
+			Index:     i + 1,
+			Title:     doc.Title,
+			Relevance: doc.Score * 100,
+			Section:   doc.Section(),
+			Content:   doc.Text(),
+		}
+		documentData.Symbol, documentData.StartLine, documentData.EndLine = doc.Symbol()
+
+		entry, err := r.renderDocument(documentData, sel)
+		if err != nil {
+			return "", nil, err
+		}
+		entryTokens := r.opts.Tokens.Count(entry)
+
+		if usedTokens+entryTokens > maxTokens {
+			// Potong konten dokumen ini agar mengisi sisa anggaran, lalu berhenti
+			documentData.Content = ""
+			empty, err := r.renderDocument(documentData, sel)
+			if err != nil {
+				return "", nil, err
+			}
+			contentTokens := maxTokens - usedTokens - r.opts.Tokens.Count(empty) - r.opts.Tokens.Count(truncatedMarker)
+			if contentTokens < minTruncatedTokens {
+				return contextBuilder.String(), docs[:i], nil
+			}
+
+			documentData.Content = tokenizer.Truncate(r.opts.Tokens, doc.Text(), contentTokens) + truncatedMarker
+			entry, err = r.renderDocument(documentData, sel)
+			if err != nil {
+				return "", nil, err
+			}
+			contextBuilder.WriteString(entry)
+			return contextBuilder.String(), docs[:i+1], nil
+		}
+
+		usedTokens += entryTokens
+		contextBuilder.WriteString(entry)
+	}
+
+	return contextBuilder.String(), docs, nil
+}
+
+// renderDocument merender satu entri konteks dokumen beserta pemisah antar entri
+func (r *Retriever) renderDocument(data prompt.DocumentData, sel prompt.Selector) (string, error) {
+	entry, err := r.opts.Prompts.Render(prompt.ContextDocument, sel, data)
+	if err != nil {
+		return "", err
+	}
+	return entry + "\n\n", nil
+}
+
+// fitHistory mengubah riwayat percakapan menjadi pesan chat yang muat dalam maxTokens. Pesan
+// terbaru diutamakan; pesan terlama dibuang atau, dengan HistorySummarize, diganti ringkasan.
+func (r *Retriever) fitHistory(ctx context.Context, data prompt.Data, maxTokens int, search SearchOptions) ([]embedding.ChatCompletionMessage, error) {
+	history := data.History
+	if r.historyTokens(history) <= maxTokens {
+		return chatMessages(history), nil
+	}
+
+	if r.opts.HistoryStrategy != HistorySummarize {
+		start := r.keepNewest(history, maxTokens)
+		log.Printf("Dropped %d of %d conversation messages to fit the context window of %s", start, len(history), r.chatAPI.ModelName())
+		return chatMessages(history[start:]), nil
+	}
+
+	// Sisakan ruang untuk ringkasan beserta teks template pembungkusnya
+	sel := search.selector()
+	wrapper, err := r.opts.Prompts.Render(prompt.HistorySummary, sel, data)
+	if err != nil {
+		return nil, err
+	}
+	keepTokens := maxTokens - maxSummaryTokens - r.messageTokens(wrapper)
+
+	// Ringkasan tersimpan dipakai selama pesan setelahnya masih muat, sehingga model tidak
+	// dipanggil di setiap giliran
+	cacheKey := summaryCacheKey(search)
+	cached, hasCached := r.summaries.get(cacheKey, history)
+	start, summary := 0, ""
+	if hasCached && r.historyTokens(history[cached.count:]) <= keepTokens {
+		start, summary = cached.count, cached.summary
+	} else {
+		// Pesan terbaru hanya mengisi separuh anggaran agar giliran berikutnya masih muat
+		// bersama ringkasan yang sama
+		start = r.keepNewest(history, keepTokens/2)
+
+		// Ringkasan lama diperbarui dengan pesan yang baru dibuang saja
+		previous, from := "", 0
+		if hasCached && cached.count <= start {
+			previous, from = cached.summary, cached.count
+		}
+		summary = r.summarizeHistory(ctx, data, previous, history[from:start], sel)
+		if summary == "" {
+			log.Printf("Dropped %d of %d conversation messages to fit the context window of %s", start, len(history), r.chatAPI.ModelName())
+			return chatMessages(history[start:]), nil
+		}
+		r.summaries.put(cacheKey, history[:start], summary)
+		log.Printf("Summarized %d of %d conversation messages to fit the context window of %s", start, len(history), r.chatAPI.ModelName())
+	}
+
+	summaryData := data
+	summaryData.Summary = summary
+	summaryMessage, err := r.opts.Prompts.Render(prompt.HistorySummary, sel, summaryData)
+	if err != nil {
+		return nil, err
+	}
+	return append([]embedding.ChatCompletionMessage{{Role: "system", Content: summaryMessage}}, chatMessages(history[start:])...), nil
+}
+
+// keepNewest mengembalikan indeks pesan pertama yang dipertahankan agar pesan terbaru muat
+// dalam maxTokens
+func (r *Retriever) keepNewest(history []model.ChatMessage, maxTokens int) int {
+	start := len(history)
+	usedTokens := 0
+	for start > 0 {
+		msgTokens := r.messageTokens(history[start-1].Content)
+		if usedTokens+msgTokens > maxTokens {
+			break
+		}
+		usedTokens += msgTokens
+		start--
+	}
+	return start
+}
+
+// historyTokens menghitung token semua pesan di history
+func (r *Retriever) historyTokens(history []model.ChatMessage) int {
+	total := 0
+	for _, msg := range history {
+		total += r.messageTokens(msg.Content)
+	}
+	return total
+}
+
+// summarizeHistory meminta model chat meringkas messages, dilanjutkan dari ringkasan previous
+// jika ada. Pesan terlama dibuang jika permintaan ringkasan sendiri tidak muat di context window.
+// Ringkasan kosong dikembalikan saat gagal.
+func (r *Retriever) summarizeHistory(ctx context.Context, data prompt.Data, previous string, messages []model.ChatMessage, sel prompt.Selector) string {
+	data.Summary = previous
+	var request string
+	for len(messages) > 0 {
+		data.History = messages
+		var err error
+		request, err = r.opts.Prompts.Render(prompt.SummarizeHistory, sel, data)
+		if err != nil {
+			log.Printf("Error rendering history summary prompt: %v", err)
+			return ""
+		}
+		if r.messageTokens(request) <= r.promptBudget() {
+			break
+		}
+		messages = messages[1:]
+	}
+	if len(messages) == 0 {
+		return ""
+	}
+
+	completion, err := r.chatAPI.ChatCompletion(ctx, []embedding.ChatCompletionMessage{{Role: "user", Content: request}})
+	if err != nil {
+		log.Printf("Error summarizing conversation history: %v", err)
+		return ""
+	}
+	return tokenizer.Truncate(r.opts.Tokens, strings.TrimSpace(completion.Content), maxSummaryTokens)
+}
+
+// maxCachedSummaries membatasi jumlah ringkasan riwayat yang disimpan di memori
+const maxCachedSummaries = 1000
+
+// cachedSummary adalah ringkasan count pesan pertama sebuah percakapan
+type cachedSummary struct {
+	count   int
+	hash    [sha256.Size]byte
+	summary string
+}
+
+// summaryCache menyimpan ringkasan riwayat terakhir per percakapan. Ringkasan hanya dipakai jika
+// pesan yang diringkas masih sama dengan awal riwayat percakapan.
+type summaryCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedSummary
+	// order mencatat urutan kunci untuk membuang ringkasan terlama saat cache penuh
+	order []string
+}
+
+// newSummaryCache membuat summaryCache kosong
+func newSummaryCache() *summaryCache {
+	return &summaryCache{entries: make(map[string]cachedSummary)}
+}
+
+// summaryCacheKey mengembalikan kunci cache ringkasan untuk percakapan dan pilihan template
+// pencarian, atau string kosong jika pencarian tidak terkait percakapan
+func summaryCacheKey(search SearchOptions) string {
+	if search.Session == "" {
+		return ""
+	}
+	return search.Session + "\x00" + search.Language + "\x00" + search.Collection
+}
+
+// get mengembalikan ringkasan tersimpan untuk key jika pesan yang diringkas adalah awal history
+func (c *summaryCache) get(key string, history []model.ChatMessage) (cachedSummary, bool) {
+	if key == "" {
+		return cachedSummary{}, false
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok || entry.count > len(history) || hashMessages(history[:entry.count]) != entry.hash {
+		return cachedSummary{}, false
+	}
+	return entry, true
+}
+
+// put menyimpan ringkasan messages untuk key
+func (c *summaryCache) put(key string, messages []model.ChatMessage, summary string) {
+	if key == "" {
+		return
+	}
+	entry := cachedSummary{count: len(messages), hash: hashMessages(messages), summary: summary}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+		if len(c.order) > maxCachedSummaries {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	c.entries[key] = entry
+}
+
+// hashMessages menghitung hash role dan isi messages
+func hashMessages(messages []model.ChatMessage) [sha256.Size]byte {
+	h := sha256.New()
+	for _, msg := range messages {
+		h.Write([]byte(msg.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Content))
+		h.Write([]byte{0})
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// chatMessages mengubah riwayat percakapan menjadi pesan untuk model LLM
+func chatMessages(history []model.ChatMessage) []embedding.ChatCompletionMessage {
+	messages := make([]embedding.ChatCompletionMessage, 0, len(history))
+	for _, msg := range history {
+		messages = append(messages, embedding.ChatCompletionMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,99 @@
+package rag
+
+import (
+	"context"
+	"rag-chat-bot/internal/embedding"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/prompt"
+	"strings"
+	"testing"
+)
+
+// fakeChat adalah ChatModel yang mengembalikan jawaban tetap dan menghitung jumlah panggilan
+type fakeChat struct {
+	reply string
+	calls int
+}
+
+func (f *fakeChat) ChatCompletion(ctx context.Context, messages []embedding.ChatCompletionMessage) (*embedding.Completion, error) {
+	f.calls++
+	return &embedding.Completion{Content: f.reply, FinishReason: "stop"}, nil
+}
+
+func (f *fakeChat) ModelName() string    { return "fake" }
+func (f *fakeChat) ContextWindow() int   { return 2000 }
+func (f *fakeChat) MaxOutputTokens() int { return 500 }
+
+func conversation(turns int) []model.ChatMessage {
+	var history []model.ChatMessage
+	for i := 0; i < turns; i++ {
+		history = append(history,
+			model.ChatMessage{Role: "user", Content: strings.Repeat("pertanyaan ", 20)},
+			model.ChatMessage{Role: "assistant", Content: strings.Repeat("jawaban ", 25)},
+		)
+	}
+	return history
+}
+
+func messagesTokens(r *Retriever, messages []embedding.ChatCompletionMessage) int {
+	total := 0
+	for _, msg := range messages {
+		total += r.messageTokens(msg.Content)
+	}
+	return total
+}
+
+func TestFitHistoryTruncate(t *testing.T) {
+	r := NewRetriever(nil, nil, &fakeChat{}, RetrieverOptions{})
+	history := conversation(20)
+
+	messages, err := r.fitHistory(context.Background(), prompt.Data{History: history}, 400, SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messagesTokens(r, messages); got > 400 {
+		t.Errorf("history uses %d tokens, budget is 400", got)
+	}
+	if last := messages[len(messages)-1]; last.Content != history[len(history)-1].Content {
+		t.Errorf("newest message was dropped")
+	}
+}
+
+func TestFitHistorySummarize(t *testing.T) {
+	chat := &fakeChat{reply: strings.Repeat("ringkasan ", 200)}
+	r := NewRetriever(nil, nil, chat, RetrieverOptions{HistoryStrategy: HistorySummarize})
+	search := SearchOptions{Session: "session-1"}
+	history := conversation(20)
+
+	messages, err := r.fitHistory(context.Background(), prompt.Data{History: history}, 600, search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messagesTokens(r, messages); got > 600 {
+		t.Errorf("history with summary uses %d tokens, budget is 600", got)
+	}
+	if messages[0].Role != "system" || !strings.Contains(messages[0].Content, "ringkasan") {
+		t.Errorf("first message is not the summary: %+v", messages[0])
+	}
+	if chat.calls != 1 {
+		t.Fatalf("expected 1 summary call, got %d", chat.calls)
+	}
+
+	// Giliran berikutnya memakai ringkasan tersimpan selama pesan setelahnya masih muat
+	history = append(history, model.ChatMessage{Role: "user", Content: "lanjut"})
+	if _, err := r.fitHistory(context.Background(), prompt.Data{History: history}, 600, search); err != nil {
+		t.Fatal(err)
+	}
+	if chat.calls != 1 {
+		t.Errorf("expected cached summary to be reused, got %d calls", chat.calls)
+	}
+
+	// Riwayat yang berubah di awal tidak boleh memakai ringkasan lama
+	history[0].Content = "pertanyaan lain"
+	if _, err := r.fitHistory(context.Background(), prompt.Data{History: history}, 600, search); err != nil {
+		t.Fatal(err)
+	}
+	if chat.calls != 2 {
+		t.Errorf("expected a new summary after the history changed, got %d calls", chat.calls)
+	}
+}
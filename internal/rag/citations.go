@@ -0,0 +1,105 @@
+package rag
+
+import (
+	"rag-chat-bot/internal/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// citationPattern mencocokkan penanda sitasi seperti [1], [1, 3] atau [Dokumen 2]
+var citationPattern = regexp.MustCompile(`\[(?i:(?:dokumen|document|sumber|source)\s*)?(\d+(?:\s*,\s*\d+)*)\]`)
+
+// inlineCodePattern mencocokkan kode inline seperti `arr[1]`
+var inlineCodePattern = regexp.MustCompile("``[^`]*``|`[^`]*`")
+
+// maxExcerptRunes membatasi panjang kutipan potongan yang dikirim ke klien
+const maxExcerptRunes = 300
+
+// ParseCitations mengembalikan nomor sumber yang dirujuk jawaban dengan penanda [n], sesuai
+// urutan kemunculan pertamanya dan tanpa duplikat. Indeks di dalam blok kode, kode inline, atau
+// tepat setelah nama seperti arr[1] dan f()[0] tidak dianggap sitasi.
+func ParseCitations(answer string) []int {
+	text := withoutCode(answer)
+
+	var citations []int
+	seen := make(map[int]bool)
+	lastEnd := -1
+	for _, loc := range citationPattern.FindAllStringSubmatchIndex(text, -1) {
+		if !citationBoundary(text, loc[0], lastEnd) {
+			continue
+		}
+		lastEnd = loc[1]
+		for _, part := range strings.Split(text[loc[2]:loc[3]], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || seen[n] {
+				continue
+			}
+			seen[n] = true
+			citations = append(citations, n)
+		}
+	}
+	return citations
+}
+
+// citationBoundary memeriksa karakter sebelum penanda di posisi start. Penanda setelah huruf,
+// angka, garis bawah atau ")" adalah indeks kode; setelah "]" hanya diterima jika melanjutkan
+// penanda sebelumnya yang berakhir di lastEnd, seperti [1][2].
+func citationBoundary(text string, start, lastEnd int) bool {
+	if start == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:start])
+	switch {
+	case r == ']':
+		return start == lastEnd
+	case r == ')' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return false
+	default:
+		return true
+	}
+}
+
+// withoutCode mengosongkan blok kode berpagar (``` atau ~~~) dan kode inline di teks
+func withoutCode(text string) string {
+	lines := strings.SplitAfter(text, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			lines[i] = "\n"
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			lines[i] = "\n"
+		default:
+			lines[i] = inlineCodePattern.ReplaceAllString(line, " ")
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// markCitations menandai sumber yang dirujuk jawaban. Nomor yang tidak ada di sources diabaikan.
+func markCitations(answer string, sources []model.Source) []model.Source {
+	for _, n := range ParseCitations(answer) {
+		if n >= 1 && n <= len(sources) {
+			sources[n-1].Cited = true
+		}
+	}
+	return sources
+}
+
+// excerpt memendekkan teks potongan menjadi paling banyak maxExcerptRunes karakter
+func excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxExcerptRunes {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxExcerptRunes])) + "…"
+}
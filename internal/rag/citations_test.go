@@ -0,0 +1,64 @@
+package rag
+
+import (
+	"rag-chat-bot/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestParseCitations(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   []int
+	}{
+		{"none", "Tidak ada sumber.", nil},
+		{"single", "Refund diproses dalam 7 hari [1].", []int{1}},
+		{"list and duplicates", "Lihat [2, 1] dan [2].", []int{2, 1}},
+		{"named markers", "Menurut [Dokumen 3] dan [source 4].", []int{3, 4}},
+		{"adjacent markers", "Didukung dua sumber [1][3].", []int{1, 3}},
+		{"start of answer", "[2] menyebutkan batasnya.", []int{2}},
+		{"after punctuation", "Batasnya 10 item.[1]", []int{1}},
+		{"index expression", "Ambil elemen dengan arr[1] atau items_2[0].", nil},
+		{"multi index", "Matriks x[0, 1] dan grid[0][1].", nil},
+		{"call result", "Gunakan f()[0] lalu kutip [2].", []int{2}},
+		{"inline code", "Panggil `arr[1]` sesuai [3].", []int{3}},
+		{"fenced code", "Contoh:\n```go\nx := arr[1]\ny := [2]int{}\n```\nSumber [4].", []int{4}},
+		{"tilde fence", "~~~\n[1]\n~~~\n[2]", []int{2}},
+		{"unclosed fence", "Lihat [1].\n```\n[2]", []int{1}},
+		{"not a number", "Opsi [a] dan [1a].", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCitations(tt.answer); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCitations(%q) = %v, want %v", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkCitations(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   []bool
+	}{
+		{"cited sources", "Menurut [1] dan [3].", []bool{true, false, true}},
+		{"out of range", "Lihat [0], [4] dan [99].", []bool{false, false, false}},
+		{"mixed", "Lihat [2, 7].", []bool{false, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources := make([]model.Source, 3)
+			var got []bool
+			for _, source := range markCitations(tt.answer, sources) {
+				got = append(got, source.Cited)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cited = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package rag
+
+import (
+	"rag-chat-bot/internal/model"
+	"sort"
+)
+
+// Mode retrieval yang didukung Retriever
+const (
+	ModeVector  = "vector"
+	ModeKeyword = "keyword"
+	ModeHybrid  = "hybrid"
+)
+
+// rankedList adalah daftar hasil yang sudah terurut beserta bobotnya dalam fusion
+type rankedList struct {
+	results []*model.DocumentWithScore
+	weight  float64
+}
+
+// fuseReciprocalRank menggabungkan beberapa daftar terurut dengan reciprocal rank fusion:
+// skor setiap potongan adalah jumlah bobot / (k + peringkat) dari semua daftar yang memuatnya.
+// Skor dinormalisasi ke 0-1 terhadap skor maksimum yang mungkin (peringkat pertama di semua daftar).
+func fuseReciprocalRank(lists []rankedList, k int, limit int) []*model.DocumentWithScore {
+	if k <= 0 {
+		k = 60
+	}
+
+	fused := make(map[int]*model.DocumentWithScore)
+	var order []int
+	maxScore := 0.0
+
+	for _, list := range lists {
+		maxScore += list.weight / float64(k+1)
+
+		for rank, result := range list.results {
+			key := result.ID
+			if result.Chunk != nil {
+				key = result.Chunk.ID
+			}
+
+			existing, ok := fused[key]
+			if !ok {
+				copied := *result
+				copied.Score = 0
+				existing = &copied
+				fused[key] = existing
+				order = append(order, key)
+			}
+
+			existing.Score += list.weight / float64(k+rank+1)
+			if result.VectorScore > existing.VectorScore {
+				existing.VectorScore = result.VectorScore
+			}
+			if result.KeywordScore > existing.KeywordScore {
+				existing.KeywordScore = result.KeywordScore
+			}
+		}
+	}
+
+	results := make([]*model.DocumentWithScore, 0, len(order))
+	for _, key := range order {
+		if maxScore > 0 {
+			fused[key].Score /= maxScore
+		}
+		results = append(results, fused[key])
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,128 @@
+package rag
+
+import (
+	"math"
+	"rag-chat-bot/internal/model"
+	"testing"
+)
+
+// chunkResult membuat hasil pencarian untuk potongan id dari dokumen docID
+func chunkResult(docID, id int, vectorScore, keywordScore float64) *model.DocumentWithScore {
+	return &model.DocumentWithScore{
+		Document:     model.Document{ID: docID},
+		Chunk:        &model.DocumentChunk{ID: id, DocumentID: docID},
+		Score:        vectorScore + keywordScore,
+		VectorScore:  vectorScore,
+		KeywordScore: keywordScore,
+	}
+}
+
+func TestFuseReciprocalRank(t *testing.T) {
+	type fused struct {
+		chunk int
+		score float64
+	}
+	tests := []struct {
+		name  string
+		lists []rankedList
+		k     int
+		limit int
+		want  []fused
+	}{
+		{
+			name: "overlapping lists with tie",
+			lists: []rankedList{
+				{weight: 1, results: []*model.DocumentWithScore{chunkResult(1, 10, 0.9, 0), chunkResult(1, 11, 0.8, 0), chunkResult(2, 20, 0.7, 0)}},
+				{weight: 1, results: []*model.DocumentWithScore{chunkResult(1, 11, 0, 0.5), chunkResult(1, 10, 0, 0.4), chunkResult(3, 30, 0, 0.3)}},
+			},
+			k: 1,
+			// Skor sama dipertahankan dalam urutan kemunculan pertama
+			want: []fused{{10, 5.0 / 6}, {11, 5.0 / 6}, {20, 0.25}, {30, 0.25}},
+		},
+		{
+			name: "disjoint lists",
+			lists: []rankedList{
+				{weight: 1, results: []*model.DocumentWithScore{chunkResult(1, 10, 0.9, 0), chunkResult(1, 11, 0.8, 0)}},
+				{weight: 1, results: []*model.DocumentWithScore{chunkResult(2, 20, 0, 0.5), chunkResult(2, 21, 0, 0.4)}},
+			},
+			k:    1,
+			want: []fused{{10, 0.5}, {20, 0.5}, {11, 1.0 / 3}, {21, 1.0 / 3}},
+		},
+		{
+			name: "zero weight list",
+			lists: []rankedList{
+				{weight: 1, results: []*model.DocumentWithScore{chunkResult(1, 10, 0.9, 0), chunkResult(1, 11, 0.8, 0)}},
+				{weight: 0, results: []*model.DocumentWithScore{chunkResult(2, 20, 0, 0.5), chunkResult(1, 10, 0, 0.4)}},
+			},
+			k:    1,
+			want: []fused{{10, 1}, {11, 2.0 / 3}, {20, 0}},
+		},
+		{
+			name: "all weights zero",
+			lists: []rankedList{
+				{weight: 0, results: []*model.DocumentWithScore{chunkResult(1, 10, 0.9, 0)}},
+				{weight: 0, results: []*model.DocumentWithScore{chunkResult(2, 20, 0, 0.5)}},
+			},
+			k:    1,
+			want: []fused{{10, 0}, {20, 0}},
+		},
+		{
+			name: "weighted lists",
+			lists: []rankedList{
+				{weight: 3, results: []*model.DocumentWithScore{chunkResult(1, 10, 0.9, 0), chunkResult(2, 20, 0.8, 0)}},
+				{weight: 1, results: []*model.DocumentWithScore{chunkResult(2, 20, 0, 0.5), chunkResult(1, 10, 0, 0.4)}},
+			},
+			k: 1,
+			// 10: (3/2 + 1/3) / 2, 20: (3/3 + 1/2) / 2
+			want: []fused{{10, 11.0 / 12}, {20, 0.75}},
+		},
+		{
+			name: "default k and limit",
+			lists: []rankedList{
+				{weight: 1, results: []*model.DocumentWithScore{chunkResult(1, 10, 0.9, 0), chunkResult(1, 11, 0.8, 0), chunkResult(1, 12, 0.7, 0)}},
+			},
+			limit: 2,
+			want:  []fused{{10, 1}, {11, 61.0 / 62}},
+		},
+		{
+			name:  "empty lists",
+			lists: []rankedList{{weight: 1}, {weight: 1}},
+			want:  []fused{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := fuseReciprocalRank(tt.lists, tt.k, tt.limit)
+			if len(results) != len(tt.want) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if results[i].Chunk.ID != want.chunk || math.Abs(results[i].Score-want.score) > 1e-9 {
+					t.Errorf("result %d = chunk %d score %.6f, want chunk %d score %.6f",
+						i, results[i].Chunk.ID, results[i].Score, want.chunk, want.score)
+				}
+			}
+		})
+	}
+}
+
+func TestFuseReciprocalRankMergesScores(t *testing.T) {
+	vector := chunkResult(1, 10, 0.9, 0)
+	keyword := chunkResult(1, 10, 0, 0.4)
+	results := fuseReciprocalRank([]rankedList{
+		{weight: 1, results: []*model.DocumentWithScore{vector}},
+		{weight: 1, results: []*model.DocumentWithScore{keyword}},
+	}, 60, 0)
+
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].VectorScore != 0.9 || results[0].KeywordScore != 0.4 {
+		t.Errorf("scores = %.2f/%.2f, want 0.90/0.40", results[0].VectorScore, results[0].KeywordScore)
+	}
+	// Hasil masukan tidak boleh diubah
+	if vector.Score != 0.9 || keyword.Score != 0.4 {
+		t.Errorf("input results were modified")
+	}
+}
@@ -0,0 +1,129 @@
+package rag
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Penanda awal dan akhir kata kueri di cuplikan
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+)
+
+// Highlight membuat cuplikan teks sepanjang paling banyak maxRunes karakter di sekitar bagian
+// yang paling banyak memuat kata dari query, dengan setiap kata yang cocok (termasuk bentuk
+// turunannya, misalnya "refund" pada "refunds") dibungkus <mark>...</mark>. Teks di luar penanda
+// di-escape sebagai HTML sehingga cuplikan aman ditampilkan langsung di halaman web.
+func Highlight(text, query string, maxRunes int) string {
+	text = strings.Join(strings.Fields(text), " ")
+
+	pattern := highlightPattern(query)
+	var matches [][]int
+	if pattern != nil {
+		matches = pattern.FindAllStringIndex(text, -1)
+	}
+
+	start, end := snippetWindow(text, matches, maxRunes)
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m[0] < start || m[1] > end {
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[pos:m[0]]))
+		sb.WriteString(highlightStart)
+		sb.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		sb.WriteString(highlightEnd)
+		pos = m[1]
+	}
+	sb.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// highlightPattern membuat regexp yang mencocokkan kata dari query tanpa membedakan huruf
+// besar/kecil. Kata yang lebih pendek dari tiga karakter diabaikan, dan hanya kata minimal
+// empat karakter yang juga mencocokkan bentuk turunannya.
+func highlightPattern(query string) *regexp.Regexp {
+	seen := make(map[string]bool)
+	var terms []string
+	var stems []string
+	for _, term := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		length := utf8.RuneCountInString(term)
+		if length < 3 || seen[term] {
+			continue
+		}
+		seen[term] = true
+		if length >= 4 {
+			stems = append(stems, regexp.QuoteMeta(term)+`\w*`)
+		} else {
+			terms = append(terms, regexp.QuoteMeta(term)+`\b`)
+		}
+	}
+	if len(terms)+len(stems) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(append(stems, terms...), "|") + `)`)
+}
+
+// snippetWindow memilih rentang byte [start, end) sepanjang paling banyak maxRunes karakter
+// yang memuat kecocokan terbanyak. Awal jendela dimundurkan sedikit agar kata pertama yang
+// cocok tidak berada tepat di tepi cuplikan.
+func snippetWindow(text string, matches [][]int, maxRunes int) (int, int) {
+	if maxRunes <= 0 || utf8.RuneCountInString(text) <= maxRunes {
+		return 0, len(text)
+	}
+
+	start := 0
+	if len(matches) > 0 {
+		best := 0
+		for i := range matches {
+			count := 0
+			for j := i; j < len(matches) && utf8.RuneCountInString(text[matches[i][0]:matches[j][1]]) <= maxRunes; j++ {
+				count++
+			}
+			if count > best {
+				best = count
+				start = matches[i][0]
+			}
+		}
+
+		// Mundur sekitar seperlima jendela ke awal kata
+		lead := maxRunes / 5
+		for lead > 0 && start > 0 {
+			_, size := utf8.DecodeLastRuneInString(text[:start])
+			start -= size
+			lead--
+		}
+		if start > 0 {
+			if i := strings.IndexByte(text[start:], ' '); i >= 0 && i < maxRunes/5 {
+				start += i + 1
+			}
+		}
+	}
+
+	// Maju maxRunes karakter lalu potong di batas kata
+	end := start
+	for n := 0; n < maxRunes && end < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+	if end < len(text) {
+		if i := strings.LastIndexByte(text[start:end], ' '); i > 0 {
+			end = start + i
+		}
+	}
+	return start, end
+}
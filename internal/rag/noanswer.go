@@ -0,0 +1,70 @@
+package rag
+
+import (
+	"context"
+	"rag-chat-bot/internal/embedding"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/prompt"
+)
+
+// Perilaku saat tidak ada dokumen yang relevan untuk pertanyaan
+const (
+	// NoAnswerRefuse mengirim NoAnswerMessage tanpa memanggil model chat
+	NoAnswerRefuse = "refuse"
+	// NoAnswerConversation menjawab hanya dari percakapan sebelumnya, atau NoAnswerMessage jika tidak bisa
+	NoAnswerConversation = "conversation"
+	// NoAnswerGeneral menjawab dari pengetahuan umum model dengan keterangan bahwa jawaban tidak
+	// berasal dari dokumen
+	NoAnswerGeneral = "general"
+)
+
+// noAnswerMessage mengembalikan NoAnswerMessage jika diisi, atau template prompt.NoAnswer
+func (r *Retriever) noAnswerMessage(data prompt.Data, search SearchOptions) (string, error) {
+	if r.opts.NoAnswerMessage != "" {
+		return r.opts.NoAnswerMessage, nil
+	}
+	return r.opts.Prompts.Render(prompt.NoAnswer, search.selector(), data)
+}
+
+// noAnswerMessages menyiapkan pesan untuk model LLM saat tidak ada dokumen yang relevan sesuai
+// NoAnswerMode. answerSource bernilai model.AnswerRefused jika model tidak perlu dipanggil,
+// termasuk pada mode conversation saat belum ada percakapan sebelumnya.
+func (r *Retriever) noAnswerMessages(ctx context.Context, data prompt.Data, search SearchOptions) (messages []embedding.ChatCompletionMessage, answerSource string, err error) {
+	var name string
+	switch r.opts.NoAnswerMode {
+	case NoAnswerConversation:
+		if len(data.History) == 0 {
+			return nil, model.AnswerRefused, nil
+		}
+		name = prompt.NoAnswerConversation
+		answerSource = model.AnswerFromConversation
+
+	case NoAnswerGeneral:
+		name = prompt.NoAnswerGeneral
+		answerSource = model.AnswerFromGeneral
+
+	default:
+		return nil, model.AnswerRefused, nil
+	}
+
+	instruction, err := r.opts.Prompts.Render(name, search.selector(), data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Riwayat percakapan mengisi sisa anggaran token setelah instruksi dan pertanyaan
+	available := r.promptBudget() - r.messageTokens(instruction) - r.messageTokens(data.Query)
+	if available < 0 {
+		return nil, "", ErrContextWindowExceeded
+	}
+	history, err := r.fitHistory(ctx, data, available, search)
+	if err != nil {
+		return nil, "", err
+	}
+
+	messages = append(messages, embedding.ChatCompletionMessage{Role: "system", Content: instruction})
+	messages = append(messages, history...)
+	messages = append(messages, embedding.ChatCompletionMessage{Role: "user", Content: data.Query})
+
+	return messages, answerSource, nil
+}
@@ -2,44 +2,349 @@ package rag
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"rag-chat-bot/internal/chunking"
 	"rag-chat-bot/internal/database"
 	"rag-chat-bot/internal/embedding"
 	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/tokenizer"
+	"strings"
 )
 
+// DocumentStore adalah penyimpanan dokumen yang digunakan Processor, diimplementasikan oleh
+// database.PostgresDB
+type DocumentStore interface {
+	SaveDocuments(ctx context.Context, docs []*model.Document, chunks [][]*model.DocumentChunk) ([]int, error)
+	SaveDocumentVersion(ctx context.Context, previousID int, doc *model.Document, chunks []*model.DocumentChunk) (int, error)
+	UpdateDocument(ctx context.Context, doc *model.Document, chunks []*model.DocumentChunk) error
+	DeleteDocument(ctx context.Context, id int) error
+	GetDocument(ctx context.Context, id int) (*model.Document, error)
+	ListDocuments(ctx context.Context, opts database.DocumentListOptions) ([]*model.DocumentSummary, int, error)
+	FindDocumentByContentHash(ctx context.Context, hash string) (*model.Document, error)
+	FindDocumentByMetadata(ctx context.Context, key, value string) (*model.Document, error)
+	FindDocumentsWithoutChunks(ctx context.Context, limit int) ([]*model.Document, error)
+	DocumentIDsByMetadata(ctx context.Context, key string) (map[string]int, error)
+}
+
 // Processor adalah komponen untuk memproses dokumen dalam sistem RAG
 type Processor struct {
-	db           *database.PostgresDB
-	embeddingAPI *embedding.OpenAIEmbedding
+	db           DocumentStore
+	embeddingAPI embedding.Embedder
+	chunkers     *chunking.Set
+	tokens       tokenizer.Counter
 }
 
 // NewProcessor membuat instance Processor baru
-func NewProcessor(db *database.PostgresDB, embeddingAPI *embedding.OpenAIEmbedding) *Processor {
+func NewProcessor(db DocumentStore, embeddingAPI embedding.Embedder, chunkers *chunking.Set, tokens tokenizer.Counter) *Processor {
 	return &Processor{
 		db:           db,
 		embeddingAPI: embeddingAPI,
+		chunkers:     chunkers,
+		tokens:       tokens,
 	}
 }
 
+// ErrEmptyDocument dikembalikan saat konten dokumen tidak menghasilkan potongan yang dapat di-embed
+var ErrEmptyDocument = errors.New("document has no content to index")
+
+// ProgressFunc melaporkan tahap pemrosesan dokumen ("chunking", "embedding", "saving")
+// beserta jumlah potongan yang sudah dan harus diproses
+type ProgressFunc func(stage string, done, total int)
+
+// embeddingProgressGroup adalah jumlah potongan yang di-embed sebelum kemajuan dilaporkan
+const embeddingProgressGroup = 256
+
+// ContentHash menghitung hash SHA-256 dari konten setelah spasi dinormalisasi, sehingga
+// perubahan whitespace saja tidak dianggap sebagai perubahan konten
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(content), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
 // ProcessDocument memproses dokumen dan menyimpannya dengan embedding-nya
 func (p *Processor) ProcessDocument(ctx context.Context, doc *model.Document) (int, error) {
-	// Simpan dokumen ke database
-	docID, err := p.db.SaveDocument(ctx, doc)
+	return p.ProcessDocumentWithProgress(ctx, doc, nil)
+}
+
+// ProcessDocumentWithProgress seperti ProcessDocument, tetapi melaporkan kemajuannya melalui progress
+func (p *Processor) ProcessDocumentWithProgress(ctx context.Context, doc *model.Document, progress ProgressFunc) (int, error) {
+	docIDs, err := p.ProcessDocuments(ctx, []*model.Document{doc}, progress)
+	if err != nil {
+		return 0, err
+	}
+
+	return docIDs[0], nil
+}
+
+// ProcessDocuments memproses beberapa dokumen sekaligus. Setiap dokumen dipecah menjadi
+// potongan, lalu embedding seluruh potongan dibuat melalui API batch sehingga tidak perlu
+// satu permintaan HTTP per potongan. Dokumen baru disimpan setelah semua embedding berhasil
+// dibuat, bersama potongannya dalam satu transaksi, sehingga kegagalan API embedding tidak
+// meninggalkan dokumen tanpa embedding. Jika salah satu dokumen tidak menghasilkan potongan,
+// tidak ada dokumen yang disimpan dan ErrEmptyDocument dikembalikan. progress boleh nil.
+func (p *Processor) ProcessDocuments(ctx context.Context, docs []*model.Document, progress ProgressFunc) ([]int, error) {
+	if progress == nil {
+		progress = func(string, int, int) {}
+	}
+
+	// Pecah konten setiap dokumen menjadi potongan
+	progress("chunking", 0, 0)
+	docChunks := make([][]*model.DocumentChunk, len(docs))
+	var allChunks []*model.DocumentChunk
+	for i, doc := range docs {
+		doc.ContentHash = ContentHash(doc.Content)
+		chunks, err := p.chunkDocument(0, doc)
+		if err != nil {
+			return nil, fmt.Errorf("error chunking document %q: %w", doc.Title, err)
+		}
+		docChunks[i] = chunks
+		allChunks = append(allChunks, docChunks[i]...)
+	}
+
+	// Generate embedding untuk semua potongan
+	if err := p.embedChunks(ctx, allChunks, progress); err != nil {
+		return nil, err
+	}
+
+	// Simpan dokumen beserta potongan dan embedding-nya
+	progress("saving", len(allChunks), len(allChunks))
+	docIDs, err := p.db.SaveDocuments(ctx, docs, docChunks)
+	if err != nil {
+		return nil, fmt.Errorf("error saving document: %w", err)
+	}
+
+	return docIDs, nil
+}
+
+// IngestDocument menyimpan dokumen baru dengan memeriksa duplikat berdasarkan hash konten.
+// Jika dokumen aktif dengan konten yang sama sudah ada, onDuplicate menentukan apa yang terjadi:
+// DuplicateSkip (default) mengembalikan dokumen yang ada, DuplicateReplace mengganti judul dan
+// metadatanya, dan DuplicateNewVersion menyimpan dokumen sebagai versi baru. Konten duplikat
+// tidak pernah di-embed ulang kecuali versi baru dipecah dengan chunker lain. progress boleh nil.
+func (p *Processor) IngestDocument(ctx context.Context, doc *model.Document, onDuplicate string, progress ProgressFunc) (*model.IngestResult, error) {
+	doc.ContentHash = ContentHash(doc.Content)
+
+	existing, err := p.db.FindDocumentByContentHash(ctx, doc.ContentHash)
+	if errors.Is(err, database.ErrDocumentNotFound) {
+		var docID int
+		docID, err = p.ProcessDocumentWithProgress(ctx, doc, progress)
+		if errors.Is(err, database.ErrDuplicateDocument) {
+			// Konten yang sama baru saja disimpan oleh permintaan lain
+			existing, err = p.db.FindDocumentByContentHash(ctx, doc.ContentHash)
+		} else if err != nil {
+			return nil, err
+		} else {
+			return &model.IngestResult{DocID: docID, Action: model.IngestCreated, Version: 1}, nil
+		}
+	}
 	if err != nil {
-		return 0, fmt.Errorf("error saving document: %w", err)
+		return nil, err
+	}
+
+	switch onDuplicate {
+	case model.DuplicateReplace:
+		updated, _, err := p.UpdateDocument(ctx, existing.ID, &model.UpdateDocumentRequest{
+			Title:    &doc.Title,
+			Metadata: doc.Metadata,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &model.IngestResult{DocID: updated.ID, Action: model.IngestReplaced, DuplicateOf: existing.ID, Version: updated.Version}, nil
+
+	case model.DuplicateNewVersion:
+		// Potongan versi lama disalin kecuali judul/metadata baru membuatnya dipecah dengan chunker lain
+		var chunks []*model.DocumentChunk
+		if chunking.DetectFormat(doc.Title, doc.Metadata) != chunking.DetectFormat(existing.Title, existing.Metadata) ||
+			chunking.DetectLanguage(doc.Title, doc.Metadata) != chunking.DetectLanguage(existing.Title, existing.Metadata) {
+			chunks, err = p.chunkDocument(0, doc)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.embedChunks(ctx, chunks, progress); err != nil {
+				return nil, err
+			}
+		}
+		docID, err := p.db.SaveDocumentVersion(ctx, existing.ID, doc, chunks)
+		if err != nil {
+			return nil, fmt.Errorf("error saving document version: %w", err)
+		}
+		return &model.IngestResult{DocID: docID, Action: model.IngestNewVersion, DuplicateOf: existing.ID, Version: doc.Version}, nil
+
+	default:
+		return &model.IngestResult{DocID: existing.ID, Action: model.IngestSkipped, DuplicateOf: existing.ID, Version: existing.Version}, nil
+	}
+}
+
+// UpdateDocument menerapkan perubahan pada dokumen. Dokumen hanya dipecah dan di-embed ulang
+// jika kontennya berubah atau perubahan judul/metadata membuatnya dipecah dengan chunker lain;
+// selain itu hanya judul dan metadata yang diperbarui. Nilai kembalian reembedded menunjukkan
+// apakah embedding dibuat ulang.
+func (p *Processor) UpdateDocument(ctx context.Context, id int, update *model.UpdateDocumentRequest) (doc *model.Document, reembedded bool, err error) {
+	doc, err = p.db.GetDocument(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	oldContent := doc.Content
+	oldFormat := chunking.DetectFormat(doc.Title, doc.Metadata)
+	oldLanguage := chunking.DetectLanguage(doc.Title, doc.Metadata)
+
+	if update.Title != nil {
+		doc.Title = *update.Title
+	}
+	if update.Content != nil {
+		doc.Content = *update.Content
+	}
+	if update.Metadata != nil {
+		doc.Metadata = update.Metadata
+	}
+
+	reembedded = doc.Content != oldContent ||
+		chunking.DetectFormat(doc.Title, doc.Metadata) != oldFormat ||
+		chunking.DetectLanguage(doc.Title, doc.Metadata) != oldLanguage
+
+	if doc.Content != oldContent {
+		doc.ContentHash = ContentHash(doc.Content)
+	}
+
+	var chunks []*model.DocumentChunk
+	if reembedded {
+		chunks, err = p.chunkDocument(doc.ID, doc)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := p.embedChunks(ctx, chunks, nil); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := p.db.UpdateDocument(ctx, doc, chunks); err != nil {
+		return nil, false, fmt.Errorf("error updating document: %w", err)
+	}
+
+	return doc, reembedded, nil
+}
+
+// embedChunks membuat embedding untuk setiap potongan melalui API batch. Jika progress tidak nil,
+// potongan di-embed per kelompok dan kemajuan dilaporkan setelah setiap kelompok.
+func (p *Processor) embedChunks(ctx context.Context, chunks []*model.DocumentChunk, progress ProgressFunc) error {
+	texts := make([]string, len(chunks))
+	totalTokens := 0
+	for i, chunk := range chunks {
+		texts[i] = chunk.Content
+		totalTokens += p.tokens.Count(chunk.Content)
+	}
+
+	log.Printf("Embedding %d chunks with %s (%d tokens, estimated cost $%.6f)",
+		len(texts), p.embeddingAPI.ModelName(), totalTokens, embedding.EstimateCost(p.embeddingAPI.ModelName(), totalTokens))
+
+	group := len(texts)
+	if progress != nil {
+		group = embeddingProgressGroup
+		progress("embedding", 0, len(texts))
 	}
 
-	// Generate embedding untuk dokumen
-	embedding, err := p.embeddingAPI.CreateEmbedding(ctx, doc.Content)
+	for start := 0; start < len(texts); start += group {
+		end := min(start+group, len(texts))
+
+		embeddings, err := p.embeddingAPI.CreateEmbeddings(ctx, texts[start:end])
+		if err != nil {
+			return fmt.Errorf("error creating embedding: %w", err)
+		}
+
+		for i, chunk := range chunks[start:end] {
+			chunk.Embedding = embeddings[i]
+		}
+		if progress != nil {
+			progress("embedding", end, len(texts))
+		}
+	}
+	return nil
+}
+
+// ReindexOrphanDocuments membuat potongan dan embedding untuk dokumen yang belum memilikinya.
+// Dokumen yang gagal diproses dicatat di log dan dilewati; jumlah dokumen yang berhasil
+// di-embed dikembalikan.
+func (p *Processor) ReindexOrphanDocuments(ctx context.Context) (int, error) {
+	docs, err := p.db.FindDocumentsWithoutChunks(ctx, 100)
 	if err != nil {
-		return 0, fmt.Errorf("error creating embedding: %w", err)
+		return 0, err
 	}
 
-	// Simpan embedding
-	if err := p.db.SaveEmbedding(ctx, docID, embedding); err != nil {
-		return 0, fmt.Errorf("error saving embedding: %w", err)
+	reindexed := 0
+	for _, doc := range docs {
+		chunks, err := p.chunkDocument(doc.ID, doc)
+		if err != nil {
+			log.Printf("Error chunking document %d: %v", doc.ID, err)
+			continue
+		}
+		if err := p.embedChunks(ctx, chunks, nil); err != nil {
+			log.Printf("Error embedding document %d: %v", doc.ID, err)
+			continue
+		}
+		if err := p.db.UpdateDocument(ctx, doc, chunks); err != nil {
+			log.Printf("Error saving chunks for document %d: %v", doc.ID, err)
+			continue
+		}
+		reindexed++
+	}
+
+	return reindexed, nil
+}
+
+// GetDocument mengambil dokumen berdasarkan ID
+func (p *Processor) GetDocument(ctx context.Context, id int) (*model.Document, error) {
+	return p.db.GetDocument(ctx, id)
+}
+
+// ListDocuments mengambil daftar dokumen sesuai opsi pencarian dan paginasi
+func (p *Processor) ListDocuments(ctx context.Context, opts database.DocumentListOptions) ([]*model.DocumentSummary, int, error) {
+	return p.db.ListDocuments(ctx, opts)
+}
+
+// DeleteDocument menghapus dokumen beserta seluruh potongan dan embedding-nya
+func (p *Processor) DeleteDocument(ctx context.Context, id int) error {
+	return p.db.DeleteDocument(ctx, id)
+}
+
+// chunkDocument memecah konten dokumen menjadi potongan yang siap di-embed
+// menggunakan Chunker yang sesuai dengan format dokumen. ErrEmptyDocument dikembalikan jika
+// tidak ada potongan yang dihasilkan.
+func (p *Processor) chunkDocument(docID int, doc *model.Document) ([]*model.DocumentChunk, error) {
+	format := chunking.DetectFormat(doc.Title, doc.Metadata)
+	chunker := p.chunkers.For(format)
+
+	// Potongan kode sumber menyimpan bahasanya agar dapat ditampilkan di konteks dan sitasi
+	language := ""
+	if format == chunking.FormatGo || format == chunking.FormatCode {
+		language = chunking.DetectLanguage(doc.Title, doc.Metadata)
 	}
 
-	return docID, nil
+	var chunks []*model.DocumentChunk
+	for _, c := range chunker.Chunk(doc.Content) {
+		if language != "" {
+			if c.Metadata == nil {
+				c.Metadata = map[string]interface{}{}
+			}
+			c.Metadata["language"] = language
+		}
+		chunks = append(chunks, &model.DocumentChunk{
+			DocumentID:  docID,
+			ChunkIndex:  c.Index,
+			Content:     c.Content,
+			StartOffset: c.StartOffset,
+			EndOffset:   c.EndOffset,
+			Metadata:    c.Metadata,
+			ContentHash: ContentHash(c.Content),
+		})
+	}
+	if len(chunks) == 0 {
+		return nil, ErrEmptyDocument
+	}
+	return chunks, nil
 }
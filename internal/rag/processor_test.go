@@ -0,0 +1,79 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"rag-chat-bot/internal/chunking"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/tokenizer"
+	"testing"
+)
+
+func testProcessor(t *testing.T, store DocumentStore) *Processor {
+	t.Helper()
+	chunkers, err := chunking.NewSet(chunking.Options{Size: 100, Overlap: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewProcessor(store, &fakeEmbedder{}, chunkers, tokenizer.Estimator{})
+}
+
+func TestProcessDocumentsEmptyContent(t *testing.T) {
+	chunkers, err := chunking.NewSet(chunking.Options{Size: 100, Overlap: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tanpa database dan API embedding: dokumen kosong harus ditolak sebelum keduanya dipakai
+	p := NewProcessor(nil, nil, chunkers, tokenizer.Estimator{})
+
+	docs := []*model.Document{
+		{Title: "Valid", Content: "Dokumen ini memiliki isi."},
+		{Title: "Kosong", Content: " \n\t "},
+	}
+	if _, err := p.ProcessDocuments(context.Background(), docs, nil); !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("ProcessDocuments() error = %v, want ErrEmptyDocument", err)
+	}
+}
+
+func TestIngestDocumentConcurrentDuplicate(t *testing.T) {
+	tests := []struct {
+		onDuplicate string
+		wantAction  string
+		wantTitle   string
+	}{
+		{model.DuplicateSkip, model.IngestSkipped, "Permintaan lain"},
+		{model.DuplicateReplace, model.IngestReplaced, "Dokumen"},
+		{model.DuplicateNewVersion, model.IngestNewVersion, "Dokumen"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.onDuplicate, func(t *testing.T) {
+			store := newFakeStore()
+			p := testProcessor(t, store)
+
+			// Permintaan lain menyimpan konten yang sama setelah pemeriksaan duplikat pertama,
+			// sehingga insert ditolak dengan ErrDuplicateDocument
+			content := "Isi dokumen yang sama."
+			var otherID int
+			store.beforeSave = func() {
+				store.beforeSave = nil
+				otherID = store.insert(&model.Document{Title: "Permintaan lain", Content: content, ContentHash: ContentHash(content)})
+			}
+
+			result, err := p.IngestDocument(context.Background(), &model.Document{Title: "Dokumen", Content: content}, tt.onDuplicate, nil)
+			if err != nil {
+				t.Fatalf("IngestDocument() error = %v", err)
+			}
+			if result.Action != tt.wantAction || result.DuplicateOf != otherID {
+				t.Errorf("IngestDocument() = %+v, want action %s duplicate of %d", result, tt.wantAction, otherID)
+			}
+			doc, err := store.GetDocument(context.Background(), result.DocID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if doc.Title != tt.wantTitle {
+				t.Errorf("document title = %q, want %q", doc.Title, tt.wantTitle)
+			}
+		})
+	}
+}
@@ -2,36 +2,144 @@ package rag
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"rag-chat-bot/internal/database"
 	"rag-chat-bot/internal/embedding"
 	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/prompt"
+	"rag-chat-bot/internal/tokenizer"
 	"strings"
+	"time"
 )
 
 // Retriever adalah komponen untuk mengambil dokumen yang relevan dalam sistem RAG
 type Retriever struct {
 	db           *database.PostgresDB
-	embeddingAPI *embedding.OpenAIEmbedding
-	maxResults   int
+	embeddingAPI embedding.Embedder
+	chatAPI      embedding.ChatModel
+	opts         RetrieverOptions
+	summaries    *summaryCache
+}
+
+// RetrieverOptions mengatur perilaku Retriever
+type RetrieverOptions struct {
+	// MaxResults adalah jumlah maksimum potongan dokumen yang diambil
+	MaxResults int
+	// MaxContextTokens membatasi jumlah token konteks dokumen di dalam prompt. Konteks juga
+	// dibatasi oleh sisa context window model setelah prompt dan token jawaban.
+	MaxContextTokens int
+	// Tokens menghitung jumlah token teks
+	Tokens tokenizer.Counter
+	// Mode adalah salah satu dari ModeVector, ModeKeyword atau ModeHybrid
+	Mode string
+	// VectorWeight dan KeywordWeight adalah bobot setiap daftar dalam reciprocal rank fusion
+	VectorWeight  float64
+	KeywordWeight float64
+	// RRFK adalah konstanta k pada reciprocal rank fusion
+	RRFK int
+	// MinVectorScore membuang potongan dengan cosine similarity di bawah nilai ini, kecuali
+	// potongan yang cocok dengan kata kunci kueri. Nol menonaktifkan batas.
+	MinVectorScore float64
+	// NoAnswerMode menentukan jawaban saat tidak ada dokumen yang relevan, salah satu dari
+	// NoAnswerRefuse (default), NoAnswerConversation atau NoAnswerGeneral
+	NoAnswerMode string
+	// NoAnswerMessage menimpa template prompt.NoAnswer sebagai jawaban saat pertanyaan tidak
+	// dapat dijawab
+	NoAnswerMessage string
+	// Prompts adalah template prompt dan pesan fallback, default template bawaan
+	Prompts *prompt.Templates
+	// HistoryStrategy menentukan cara memangkas riwayat percakapan yang tidak muat di context
+	// window model, HistoryTruncate (default) atau HistorySummarize
+	HistoryStrategy string
+}
+
+// SearchOptions mengatur satu kali pencarian dokumen
+type SearchOptions struct {
+	// Filter membatasi pencarian ke dokumen dengan metadata yang sesuai
+	Filter model.MetadataFilter
+	// TopK menimpa MaxResults jika lebih dari nol
+	TopK int
+	// Mode menimpa mode retrieval Retriever jika diisi
+	Mode string
+	// MinScore membuang hasil yang Score-nya di bawah nilai ini (0-1)
+	MinScore float64
+	// Language dan Collection memilih template prompt untuk jawaban, lihat prompt.Selector
+	Language   string
+	Collection string
+	// Session mengidentifikasi percakapan untuk cache ringkasan riwayat
+	Session string
+}
+
+// selector mengembalikan pilihan template prompt untuk pencarian
+func (s SearchOptions) selector() prompt.Selector {
+	return prompt.Selector{Language: s.Language, Collection: s.Collection}
+}
+
+// Mode mengembalikan mode retrieval yang dipakai untuk pencarian dengan mode override
+func (r *Retriever) Mode(override string) string {
+	if override != "" {
+		return override
+	}
+	return r.opts.Mode
+}
+
+// Prompts mengembalikan template prompt yang dipakai Retriever
+func (r *Retriever) Prompts() *prompt.Templates {
+	return r.opts.Prompts
+}
+
+// ValidMode memeriksa apakah mode adalah mode retrieval yang didukung
+func ValidMode(mode string) bool {
+	return mode == ModeVector || mode == ModeKeyword || mode == ModeHybrid
 }
 
 // NewRetriever membuat instance Retriever baru
-func NewRetriever(db *database.PostgresDB, embeddingAPI *embedding.OpenAIEmbedding, maxResults int) *Retriever {
-	if maxResults <= 0 {
-		maxResults = 5 // Default value
+func NewRetriever(db *database.PostgresDB, embeddingAPI embedding.Embedder, chatAPI embedding.ChatModel, opts RetrieverOptions) *Retriever {
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = 5 // Default value
+	}
+	if opts.MaxContextTokens <= 0 {
+		opts.MaxContextTokens = 3000 // Default value
+	}
+	if opts.Tokens == nil {
+		opts.Tokens = tokenizer.Estimator{}
+	}
+	if opts.Mode == "" {
+		opts.Mode = ModeHybrid
+	}
+	if opts.RRFK <= 0 {
+		opts.RRFK = 60 // Default value
+	}
+	if opts.NoAnswerMode == "" {
+		opts.NoAnswerMode = NoAnswerRefuse
+	}
+	if opts.Prompts == nil {
+		opts.Prompts = prompt.Default()
+	}
+	if opts.HistoryStrategy == "" {
+		opts.HistoryStrategy = HistoryTruncate
 	}
 
 	return &Retriever{
 		db:           db,
 		embeddingAPI: embeddingAPI,
-		maxResults:   maxResults,
+		chatAPI:      chatAPI,
+		opts:         opts,
+		summaries:    newSummaryCache(),
 	}
 }
 
-// RetrieveRelevantDocuments mengambil dokumen yang relevan berdasarkan query
-func (r *Retriever) RetrieveRelevantDocuments(ctx context.Context, query string) ([]*model.DocumentWithScore, error) {
+// RetrieveRelevantDocuments mengambil dokumen yang relevan berdasarkan query. Pada mode hybrid,
+// hasil pencarian vektor dan full-text search digabung dengan reciprocal rank fusion.
+func (r *Retriever) RetrieveRelevantDocuments(ctx context.Context, query string, search SearchOptions) ([]*model.DocumentWithScore, error) {
+	limit := r.opts.MaxResults
+	if search.TopK > 0 {
+		limit = search.TopK
+	}
+	mode := r.Mode(search.Mode)
+
 	// Generate embedding untuk query
 	queryEmbedding, err := r.embeddingAPI.CreateEmbedding(ctx, query)
 	if err != nil {
@@ -39,107 +147,340 @@ func (r *Retriever) RetrieveRelevantDocuments(ctx context.Context, query string)
 	}
 
 	// Pastikan embedding memiliki dimensi yang benar
-	if len(queryEmbedding) != 1536 {
-		return nil, fmt.Errorf("invalid embedding dimension: expected 1536, got %d", len(queryEmbedding))
+	if len(queryEmbedding) != r.embeddingAPI.Dimension() {
+		return nil, fmt.Errorf("invalid embedding dimension: expected %d, got %d", r.embeddingAPI.Dimension(), len(queryEmbedding))
 	}
 
-	// Cari dokumen yang serupa berdasarkan embedding
-	docs, err := r.db.FindSimilarDocuments(ctx, queryEmbedding, r.maxResults)
-	if err != nil {
-		return nil, fmt.Errorf("error finding similar documents: %w", err)
+	var docs []*model.DocumentWithScore
+	switch mode {
+	case ModeVector:
+		// Cari dokumen yang serupa berdasarkan embedding
+		docs, err = r.db.FindSimilarDocuments(ctx, queryEmbedding, limit, search.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("error finding similar documents: %w", err)
+		}
+
+	case ModeKeyword:
+		docs, err = r.db.FindKeywordMatches(ctx, query, queryEmbedding, limit, search.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("error finding keyword matches: %w", err)
+		}
+
+	case ModeHybrid:
+		// Ambil kandidat lebih banyak dari setiap daftar agar fusion punya cukup pilihan
+		candidates := limit * 4
+
+		vectorDocs, err := r.db.FindSimilarDocuments(ctx, queryEmbedding, candidates, search.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("error finding similar documents: %w", err)
+		}
+		keywordDocs, err := r.db.FindKeywordMatches(ctx, query, queryEmbedding, candidates, search.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("error finding keyword matches: %w", err)
+		}
+
+		// Batas similarity diterapkan sebelum hasil dipotong agar kandidat berikutnya dapat menggantikan
+		docs = fuseReciprocalRank([]rankedList{
+			{results: vectorDocs, weight: r.opts.VectorWeight},
+			{results: keywordDocs, weight: r.opts.KeywordWeight},
+		}, r.opts.RRFK, 0)
+
+	default:
+		return nil, fmt.Errorf("unsupported retrieval mode: %s", mode)
 	}
 
-	return docs, nil
+	kept := docs[:0]
+	for _, doc := range docs {
+		// Potongan yang cocok dengan kata kunci tetap relevan walaupun embedding-nya kurang mirip
+		if r.opts.MinVectorScore > 0 && doc.VectorScore < r.opts.MinVectorScore && doc.KeywordScore == 0 {
+			continue
+		}
+		if doc.Score < search.MinScore {
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	if len(kept) > limit {
+		kept = kept[:limit]
+	}
+	return kept, nil
 }
 
 // BuildPromptWithContext membangun prompt untuk model LLM dengan dokumen yang relevan sebagai konteks
-func (r *Retriever) BuildPromptWithContext(ctx context.Context, userQuery string) (string, error) {
-	relevantDocs, err := r.RetrieveRelevantDocuments(ctx, userQuery)
+func (r *Retriever) BuildPromptWithContext(ctx context.Context, userQuery string, search SearchOptions) (string, error) {
+	userPrompt, _, err := r.buildPromptWithDocuments(ctx, r.promptData(userQuery, nil, search), search, r.promptBudget())
+	return userPrompt, err
+}
+
+// buildPromptWithDocuments membangun prompt dengan konteks yang muat dalam maxTokens dan
+// mengembalikan dokumen yang digunakan
+func (r *Retriever) buildPromptWithDocuments(ctx context.Context, data prompt.Data, search SearchOptions, maxTokens int) (string, []*model.DocumentWithScore, error) {
+	relevantDocs, err := r.RetrieveRelevantDocuments(ctx, data.Query, search)
 	if err != nil {
-		return "", fmt.Errorf("error retrieving relevant documents: %w", err)
+		return "", nil, fmt.Errorf("error retrieving relevant documents: %w", err)
 	}
 
 	if len(relevantDocs) == 0 {
-		return "", fmt.Errorf("no relevant documents found")
+		return "", nil, ErrNoRelevantDocuments
 	}
 
-	// Bangun prompt dengan konteks dari dokumen yang relevan
-	var contextBuilder strings.Builder
-	contextBuilder.WriteString("INFORMASI KONTEKS:\n\n")
+	// Prompt tanpa dokumen menentukan sisa anggaran token untuk konteks
+	emptyPrompt, err := r.opts.Prompts.Render(prompt.Context, search.selector(), data)
+	if err != nil {
+		return "", nil, err
+	}
+	contextTokens := maxTokens - r.messageTokens(emptyPrompt)
+	if contextTokens > r.opts.MaxContextTokens {
+		contextTokens = r.opts.MaxContextTokens
+	}
 
-	for i, doc := range relevantDocs {
-		contextBuilder.WriteString(fmt.Sprintf("[Dokumen %d] (Relevansi: %.2f%%)\n", i+1, doc.Score*100))
-		contextBuilder.WriteString(fmt.Sprintf("Judul: %s\n", doc.Title))
-		contextBuilder.WriteString(fmt.Sprintf("Konten: %s\n\n", doc.Content))
+	// Bangun konteks dari dokumen yang relevan
+	contextText, relevantDocs, err := r.fitDocuments(relevantDocs, contextTokens, search.selector())
+	if err != nil {
+		return "", nil, err
+	}
+	if len(relevantDocs) == 0 {
+		return "", nil, ErrContextWindowExceeded
 	}
 
 	// Bangun prompt lengkap
-	promptTemplate := `Anda adalah asisten AI yang membantu pengguna dengan informasi berdasarkan dokumen yang tersedia. 
-Tugas Anda adalah memberikan jawaban yang akurat, informatif, dan relevan berdasarkan informasi yang diberikan.
+	data.Context = strings.TrimSpace(contextText)
+	userPrompt, err := r.opts.Prompts.Render(prompt.Context, search.selector(), data)
+	if err != nil {
+		return "", nil, err
+	}
+	return userPrompt, relevantDocs, nil
+}
 
-%s
+// promptData menyiapkan variabel template prompt untuk pertanyaan userQuery
+func (r *Retriever) promptData(userQuery string, conversationHistory []model.ChatMessage, search SearchOptions) prompt.Data {
+	language := prompt.NormalizeLanguage(search.Language)
+	if language == "" {
+		language = r.opts.Prompts.DefaultLanguage()
+	}
+	return prompt.Data{
+		Query:      userQuery,
+		History:    previousMessages(userQuery, conversationHistory),
+		Date:       time.Now().Format("2006-01-02"),
+		Language:   language,
+		Collection: search.Collection,
+	}
+}
 
-PERTANYAAN/PERMINTAAN PENGGUNA:
-%s
+// previousMessages membuang pertanyaan saat ini dari akhir riwayat percakapan. Riwayat dari
+// ChatService sudah memuat pertanyaan saat ini sebagai pesan terakhir.
+func previousMessages(userQuery string, conversationHistory []model.ChatMessage) []model.ChatMessage {
+	if n := len(conversationHistory); n > 0 && conversationHistory[n-1].Role == "user" && conversationHistory[n-1].Content == userQuery {
+		return conversationHistory[:n-1]
+	}
+	return conversationHistory
+}
 
-PANDUAN JAWABAN:
-1. Berikan jawaban yang akurat dan relevan berdasarkan informasi yang tersedia
-2. Jika informasi tidak cukup, jelaskan keterbatasan dan sarankan apa yang mungkin bisa membantu
-3. Gunakan bahasa yang jelas dan mudah dipahami
-4. Jika ada informasi yang bertentangan, jelaskan perbedaannya
-5. Berikan sumber informasi yang digunakan (dokumen mana yang menjadi referensi)
+// ErrorMessage mengembalikan jawaban saat respons tidak dapat dihasilkan dalam bahasa pencarian
+func (r *Retriever) ErrorMessage(userQuery string, search SearchOptions) string {
+	message, err := r.opts.Prompts.Render(prompt.Error, search.selector(), r.promptData(userQuery, nil, search))
+	if err != nil {
+		log.Printf("Error rendering error message: %v", err)
+	}
+	return message
+}
 
-Jawaban Anda harus:
-- Langsung menjawab pertanyaan/permintaan pengguna
-- Menggunakan informasi dari dokumen yang relevan
-- Jelas dan terstruktur
-- Jujur tentang keterbatasan informasi yang tersedia
+// ErrNoRelevantDocuments dikembalikan saat tidak ada dokumen yang lolos batas relevansi
+var ErrNoRelevantDocuments = errors.New("no relevant documents found")
 
-Silakan berikan jawaban Anda:`
+// Answer adalah jawaban model beserta sumber dan asal jawabannya
+type Answer struct {
+	Content string
+	// Sources adalah dokumen konteks, ditandai Cited jika jawaban merujuknya dengan penanda [n]
+	Sources []model.Source
+	// AnswerSource adalah salah satu konstanta model.AnswerFrom* atau model.AnswerRefused
+	AnswerSource string
+	// Usage adalah jumlah token yang dilaporkan model chat, nil jika tidak tersedia
+	Usage *model.Usage
+}
 
-	prompt := fmt.Sprintf(promptTemplate, contextBuilder.String(), userQuery)
-	return prompt, nil
+// turn adalah pesan untuk model LLM beserta dokumen konteks dan asal jawabannya
+type turn struct {
+	messages []embedding.ChatCompletionMessage
+	docs     []*model.DocumentWithScore
+	// answerSource bernilai model.AnswerRefused jika model tidak perlu dipanggil
+	answerSource string
+	// noAnswerMessage adalah jawaban saat pertanyaan tidak dapat dijawab
+	noAnswerMessage string
 }
 
-// GenerateResponseFromContext menghasilkan respons dengan mengambil konteks yang relevan dan mengirimnya ke model LLM
-func (r *Retriever) GenerateResponseFromContext(ctx context.Context, userQuery string, conversationHistory []model.ChatMessage) (string, error) {
-	// Dapatkan prompt dengan konteks yang relevan
-	contextPrompt, err := r.BuildPromptWithContext(ctx, userQuery)
+// prepareMessages menyiapkan pesan untuk model LLM beserta dokumen yang menjadi konteksnya.
+// Konteks dan riwayat percakapan dipangkas agar muat di context window model. Jika tidak ada
+// dokumen yang relevan, pesan disiapkan sesuai NoAnswerMode.
+func (r *Retriever) prepareMessages(ctx context.Context, userQuery string, conversationHistory []model.ChatMessage, search SearchOptions) (*turn, error) {
+	data := r.promptData(userQuery, conversationHistory, search)
+	noAnswerMessage, err := r.noAnswerMessage(data, search)
 	if err != nil {
-		log.Printf("Error building prompt: %v", err)
-		return "Saya tidak dapat menemukan informasi yang relevan untuk pertanyaan Anda. Bisakah Anda memberikan lebih banyak detail atau menanyakan hal lain?", nil
+		return nil, err
 	}
-
-	// Siapkan pesan untuk model LLM
-	var messages []embedding.ChatCompletionMessage
+	data.NoAnswerMessage = noAnswerMessage
+	t := &turn{noAnswerMessage: noAnswerMessage}
 
 	// Tambahkan sistem prompt
-	systemPrompt := embedding.ChatCompletionMessage{
-		Role:    "system",
-		Content: "Anda adalah asisten AI yang membantu pengguna dengan informasi berdasarkan dokumen yang tersedia.",
+	systemPrompt, err := r.opts.Prompts.Render(prompt.System, search.selector(), data)
+	if err != nil {
+		return nil, err
 	}
-	messages = append(messages, systemPrompt)
+	available := r.promptBudget() - r.messageTokens(systemPrompt)
 
-	// Konversi history percakapan (jika ada)
-	for _, msg := range conversationHistory {
-		messages = append(messages, embedding.ChatCompletionMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		})
+	// Dapatkan prompt dengan konteks yang relevan
+	contextPrompt, docs, err := r.buildPromptWithDocuments(ctx, data, search, available)
+	if errors.Is(err, ErrContextWindowExceeded) {
+		return nil, err
+	}
+	if err != nil {
+		if !errors.Is(err, ErrNoRelevantDocuments) {
+			log.Printf("Error building prompt: %v", err)
+		}
+		t.messages, t.answerSource, err = r.noAnswerMessages(ctx, data, search)
+		return t, err
+	}
+	available -= r.messageTokens(contextPrompt)
+
+	// Riwayat percakapan mengisi sisa anggaran token
+	history, err := r.fitHistory(ctx, data, available, search)
+	if err != nil {
+		return nil, err
 	}
 
+	t.messages = append(t.messages, embedding.ChatCompletionMessage{
+		Role:    "system",
+		Content: systemPrompt,
+	})
+	t.messages = append(t.messages, history...)
+
 	// Tambahkan konteks dan query
-	contextMessage := embedding.ChatCompletionMessage{
+	t.messages = append(t.messages, embedding.ChatCompletionMessage{
 		Role:    "user",
 		Content: contextPrompt,
+	})
+	t.docs = docs
+	t.answerSource = model.AnswerFromDocuments
+
+	return t, nil
+}
+
+// GenerateResponseFromContext menghasilkan respons dengan mengambil konteks yang relevan dan
+// mengirimnya ke model LLM. Jika tidak ada dokumen yang relevan, jawaban mengikuti NoAnswerMode.
+func (r *Retriever) GenerateResponseFromContext(ctx context.Context, userQuery string, conversationHistory []model.ChatMessage, search SearchOptions) (*Answer, error) {
+	t, err := r.prepareMessages(ctx, userQuery, conversationHistory, search)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing messages: %w", err)
+	}
+	if t.answerSource == model.AnswerRefused {
+		return &Answer{Content: t.noAnswerMessage, Sources: []model.Source{}, AnswerSource: t.answerSource}, nil
 	}
-	messages = append(messages, contextMessage)
 
 	// Dapatkan respons dari model LLM
-	response, err := r.embeddingAPI.ChatCompletion(ctx, messages)
+	completion, err := r.chatAPI.ChatCompletion(ctx, t.messages)
 	if err != nil {
-		return "", fmt.Errorf("error generating response: %w", err)
+		return nil, fmt.Errorf("error generating response: %w", err)
+	}
+
+	if completion.FinishReason == "length" {
+		log.Printf("Response from %s was truncated (%d tokens used)", r.chatAPI.ModelName(), completion.Usage.TotalTokens)
 	}
 
-	return response, nil
+	return t.answer(completion), nil
+}
+
+// StreamResponseFromContext menghasilkan respons seperti GenerateResponseFromContext, tetapi
+// mengirim sumber dokumen melalui onSources lalu setiap potongan jawaban melalui onToken.
+// Teks yang sudah terkumpul tetap dikembalikan meskipun streaming berhenti karena error,
+// bersama sumber yang ditandai sesuai sitasi di teks tersebut.
+func (r *Retriever) StreamResponseFromContext(ctx context.Context, userQuery string, conversationHistory []model.ChatMessage, search SearchOptions, onSources func([]model.Source) error, onToken func(string) error) (*Answer, error) {
+	t, err := r.prepareMessages(ctx, userQuery, conversationHistory, search)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing messages: %w", err)
+	}
+	if err := onSources(buildSources(t.docs)); err != nil {
+		return nil, err
+	}
+	if t.answerSource == model.AnswerRefused {
+		answer := &Answer{Content: t.noAnswerMessage, Sources: []model.Source{}, AnswerSource: t.answerSource}
+		return answer, onToken(answer.Content)
+	}
+
+	// Model tanpa dukungan streaming tetap dapat digunakan dengan mengirim jawaban sekaligus
+	streamer, ok := r.chatAPI.(embedding.StreamingChatModel)
+	if !ok {
+		completion, err := r.chatAPI.ChatCompletion(ctx, t.messages)
+		if err != nil {
+			return nil, fmt.Errorf("error generating response: %w", err)
+		}
+		return t.answer(completion), onToken(completion.Content)
+	}
+
+	completion, err := streamer.ChatCompletionStream(ctx, t.messages, onToken)
+	if completion == nil {
+		return nil, fmt.Errorf("error generating response: %w", err)
+	}
+	answer := t.answer(completion)
+	if err != nil {
+		return answer, fmt.Errorf("error streaming response: %w", err)
+	}
+
+	if completion.FinishReason == "length" {
+		log.Printf("Response from %s was truncated", r.chatAPI.ModelName())
+	}
+
+	return answer, nil
+}
+
+// answer menyusun Answer dari jawaban model. Pada mode conversation, jawaban yang sama dengan
+// noAnswerMessage berarti model tidak menemukan jawaban di percakapan.
+func (t *turn) answer(completion *embedding.Completion) *Answer {
+	content := completion.Content
+	answerSource := t.answerSource
+	if answerSource == model.AnswerFromConversation && strings.TrimSpace(content) == strings.TrimSpace(t.noAnswerMessage) {
+		answerSource = model.AnswerRefused
+	}
+	return &Answer{
+		Content:      content,
+		Sources:      markCitations(content, buildSources(t.docs)),
+		AnswerSource: answerSource,
+		Usage:        usage(completion.Usage),
+	}
+}
+
+// usage mengubah jumlah token dari model chat untuk respons, nil jika model tidak melaporkannya
+func usage(u embedding.Usage) *model.Usage {
+	if u.TotalTokens == 0 {
+		return nil
+	}
+	return &model.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+// buildSources mengubah dokumen konteks menjadi daftar sumber untuk klien
+func buildSources(docs []*model.DocumentWithScore) []model.Source {
+	sources := make([]model.Source, 0, len(docs))
+	for i, doc := range docs {
+		source := model.Source{
+			Index:      i + 1,
+			DocumentID: doc.ID,
+			Title:      doc.Title,
+			Score:      doc.Score,
+			Excerpt:    excerpt(doc.Text()),
+			URL:        doc.SourceURL(),
+			Metadata:   doc.Metadata,
+			Section:    doc.Section(),
+		}
+		if doc.Chunk != nil {
+			source.ChunkID = doc.Chunk.ID
+			source.ChunkIndex = doc.Chunk.ChunkIndex
+			source.Anchor, _ = doc.Chunk.Metadata["section_anchor"].(string)
+		}
+		source.Symbol, source.StartLine, source.EndLine = doc.Symbol()
+		sources = append(sources, source)
+	}
+	return sources
 }
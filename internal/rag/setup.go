@@ -0,0 +1,63 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"rag-chat-bot/internal/chunking"
+	"rag-chat-bot/internal/config"
+	"rag-chat-bot/internal/database"
+	"rag-chat-bot/internal/embedding"
+	"rag-chat-bot/internal/tokenizer"
+)
+
+// Indexing adalah komponen pengindeksan dokumen yang dibangun dari konfigurasi, dipakai bersama
+// oleh server dan ragsync
+type Indexing struct {
+	Tokens    tokenizer.Counter
+	Embedder  embedding.Embedder
+	Processor *Processor
+}
+
+// NewIndexing memuat tokenizer, penyedia embedding dan chunker sesuai konfigurasi, lalu membuat
+// Processor untuk db. Dimensi embedding dipastikan sesuai dengan kolom vektor di database.
+func NewIndexing(ctx context.Context, cfg *config.Config, db *database.PostgresDB) (*Indexing, error) {
+	tokens, err := tokenizer.Load(cfg.TokenizerVocabFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading tokenizer: %w", err)
+	}
+
+	embedder, err := embedding.NewEmbedder(cfg, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing embedding provider: %w", err)
+	}
+	log.Printf("Using embedding model %s (%d dimensions)", embedder.ModelName(), embedder.Dimension())
+
+	dbDimension, err := db.EmbeddingDimension(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error checking embedding dimension: %w", err)
+	}
+	if dbDimension > 0 && dbDimension != embedder.Dimension() {
+		return nil, fmt.Errorf("embedding dimension mismatch: model %s produces %d dimensions but database column is vector(%d)",
+			embedder.ModelName(), embedder.Dimension(), dbDimension)
+	}
+
+	chunkOptions := chunking.Options{
+		Strategy: cfg.ChunkStrategy,
+		Size:     cfg.ChunkSize,
+		Overlap:  cfg.ChunkOverlap,
+	}
+	if cfg.ChunkSizeUnit == "tokens" {
+		chunkOptions.Length = tokens.Count
+	}
+	chunkers, err := chunking.NewSet(chunkOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing chunker: %w", err)
+	}
+
+	return &Indexing{
+		Tokens:    tokens,
+		Embedder:  embedder,
+		Processor: NewProcessor(db, embedder, chunkers, tokens),
+	}, nil
+}
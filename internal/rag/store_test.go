@@ -0,0 +1,184 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"rag-chat-bot/internal/database"
+	"rag-chat-bot/internal/model"
+	"sort"
+)
+
+// fakeStore adalah DocumentStore di memori yang menolak konten duplikat seperti unique index
+// content_hash di PostgreSQL
+type fakeStore struct {
+	docs   map[int]*model.Document
+	chunks map[int][]*model.DocumentChunk
+	nextID int
+	// beforeSave dipanggil sebelum dokumen disimpan, misalnya untuk menyisipkan dokumen dari
+	// permintaan lain
+	beforeSave func()
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{docs: map[int]*model.Document{}, chunks: map[int][]*model.DocumentChunk{}}
+}
+
+// insert menyimpan doc tanpa pemeriksaan dan mengembalikan ID-nya
+func (s *fakeStore) insert(doc *model.Document) int {
+	s.nextID++
+	stored := *doc
+	stored.ID = s.nextID
+	if stored.Version == 0 {
+		stored.Version = 1
+	}
+	s.docs[stored.ID] = &stored
+	return stored.ID
+}
+
+// duplicate memeriksa apakah dokumen aktif selain id sudah memiliki hash
+func (s *fakeStore) duplicate(id int, hash string) bool {
+	for _, doc := range s.docs {
+		if doc.ID != id && hash != "" && doc.ContentHash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *fakeStore) SaveDocuments(ctx context.Context, docs []*model.Document, chunks [][]*model.DocumentChunk) ([]int, error) {
+	if s.beforeSave != nil {
+		s.beforeSave()
+	}
+	for _, doc := range docs {
+		if s.duplicate(0, doc.ContentHash) {
+			return nil, database.ErrDuplicateDocument
+		}
+	}
+	ids := make([]int, len(docs))
+	for i, doc := range docs {
+		ids[i] = s.insert(doc)
+		s.chunks[ids[i]] = chunks[i]
+	}
+	return ids, nil
+}
+
+func (s *fakeStore) SaveDocumentVersion(ctx context.Context, previousID int, doc *model.Document, chunks []*model.DocumentChunk) (int, error) {
+	previous, ok := s.docs[previousID]
+	if !ok {
+		return 0, database.ErrDocumentNotFound
+	}
+	delete(s.docs, previousID)
+	doc.Version = previous.Version + 1
+	doc.PreviousVersionID = &previousID
+	if chunks == nil {
+		chunks = s.chunks[previousID]
+	}
+	id := s.insert(doc)
+	s.chunks[id] = chunks
+	return id, nil
+}
+
+func (s *fakeStore) UpdateDocument(ctx context.Context, doc *model.Document, chunks []*model.DocumentChunk) error {
+	if _, ok := s.docs[doc.ID]; !ok {
+		return database.ErrDocumentNotFound
+	}
+	if s.duplicate(doc.ID, doc.ContentHash) {
+		return database.ErrDuplicateDocument
+	}
+	stored := *doc
+	s.docs[doc.ID] = &stored
+	if chunks != nil {
+		s.chunks[doc.ID] = chunks
+	}
+	return nil
+}
+
+func (s *fakeStore) DeleteDocument(ctx context.Context, id int) error {
+	if _, ok := s.docs[id]; !ok {
+		return database.ErrDocumentNotFound
+	}
+	delete(s.docs, id)
+	delete(s.chunks, id)
+	return nil
+}
+
+func (s *fakeStore) GetDocument(ctx context.Context, id int) (*model.Document, error) {
+	doc, ok := s.docs[id]
+	if !ok {
+		return nil, database.ErrDocumentNotFound
+	}
+	copied := *doc
+	return &copied, nil
+}
+
+func (s *fakeStore) ListDocuments(ctx context.Context, opts database.DocumentListOptions) ([]*model.DocumentSummary, int, error) {
+	ids := make([]int, 0, len(s.docs))
+	for id := range s.docs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	summaries := make([]*model.DocumentSummary, 0, len(ids))
+	for _, id := range ids {
+		summaries = append(summaries, &model.DocumentSummary{ID: id, Title: s.docs[id].Title})
+	}
+	return summaries, len(summaries), nil
+}
+
+func (s *fakeStore) FindDocumentByContentHash(ctx context.Context, hash string) (*model.Document, error) {
+	for _, doc := range s.docs {
+		if doc.ContentHash == hash {
+			return s.GetDocument(ctx, doc.ID)
+		}
+	}
+	return nil, database.ErrDocumentNotFound
+}
+
+func (s *fakeStore) FindDocumentByMetadata(ctx context.Context, key, value string) (*model.Document, error) {
+	for _, doc := range s.docs {
+		if v, ok := doc.Metadata[key]; ok && fmt.Sprint(v) == value {
+			return s.GetDocument(ctx, doc.ID)
+		}
+	}
+	return nil, database.ErrDocumentNotFound
+}
+
+func (s *fakeStore) FindDocumentsWithoutChunks(ctx context.Context, limit int) ([]*model.Document, error) {
+	var docs []*model.Document
+	for id, doc := range s.docs {
+		if len(s.chunks[id]) == 0 && len(docs) < limit {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+func (s *fakeStore) DocumentIDsByMetadata(ctx context.Context, key string) (map[string]int, error) {
+	ids := make(map[string]int)
+	for id, doc := range s.docs {
+		if v, ok := doc.Metadata[key]; ok {
+			ids[fmt.Sprint(v)] = id
+		}
+	}
+	return ids, nil
+}
+
+// fakeEmbedder adalah Embedder yang menghitung jumlah teks yang di-embed
+type fakeEmbedder struct {
+	embedded int
+}
+
+func (e *fakeEmbedder) CreateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	e.embedded++
+	return []float32{1, 0, 0}, nil
+}
+
+func (e *fakeEmbedder) CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i := range texts {
+		embeddings[i], _ = e.CreateEmbedding(ctx, texts[i])
+	}
+	return embeddings, nil
+}
+
+func (e *fakeEmbedder) Dimension() int    { return 3 }
+func (e *fakeEmbedder) ModelName() string { return "fake" }
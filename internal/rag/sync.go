@@ -0,0 +1,125 @@
+package rag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"rag-chat-bot/internal/database"
+	"rag-chat-bot/internal/model"
+)
+
+// Hasil SyncDocument
+const (
+	SyncCreated   = "created"
+	SyncUpdated   = "updated"
+	SyncUnchanged = "unchanged"
+	// SyncDuplicate berarti dokumen aktif lain (dengan kunci berbeda) sudah memiliki konten yang sama
+	SyncDuplicate = "duplicate"
+)
+
+// MetadataContentHash adalah kunci metadata tempat hash konten dokumen disimpan
+const MetadataContentHash = "content_hash"
+
+// SyncDocument menyimpan dokumen yang berasal dari sumber eksternal (URL, berkas) yang
+// diidentifikasi oleh metadata key bernilai value. Dokumen baru diproses seperti biasa;
+// dokumen yang sudah ada diperbarui, tetapi hanya dipecah dan di-embed ulang jika kontennya
+// berubah, dan tidak ditulis sama sekali jika judul, konten dan metadatanya sama. Hash konten
+// disimpan di metadata content_hash. Konten yang sudah dimiliki dokumen aktif lain tidak
+// dipecah maupun di-embed. Nilai kembalian action adalah SyncCreated, SyncUpdated,
+// SyncUnchanged atau SyncDuplicate.
+func (p *Processor) SyncDocument(ctx context.Context, key, value string, doc *model.Document) (action string, docID int, err error) {
+	if doc.Metadata == nil {
+		doc.Metadata = map[string]interface{}{}
+	}
+	hash := ContentHash(doc.Content)
+	doc.Metadata[key] = value
+	doc.Metadata[MetadataContentHash] = hash
+
+	existing, err := p.db.FindDocumentByMetadata(ctx, key, value)
+	if errors.Is(err, database.ErrDocumentNotFound) {
+		duplicate, err := p.hasDuplicate(ctx, 0, hash)
+		if err != nil {
+			return "", 0, err
+		}
+		if duplicate {
+			return SyncDuplicate, 0, nil
+		}
+
+		docID, err := p.ProcessDocument(ctx, doc)
+		if errors.Is(err, database.ErrDuplicateDocument) {
+			return SyncDuplicate, 0, nil
+		}
+		if err != nil {
+			return "", 0, err
+		}
+		return SyncCreated, docID, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Konten dengan hash yang sama tidak di-embed ulang walaupun spasinya berbeda; judul dan
+	// metadata (misalnya ETag) tetap diperbarui
+	if existing.Metadata[MetadataContentHash] == doc.Metadata[MetadataContentHash] {
+		if existing.Title == doc.Title && sameMetadata(existing.Metadata, doc.Metadata) {
+			return SyncUnchanged, existing.ID, nil
+		}
+		doc.Content = existing.Content
+	} else {
+		duplicate, err := p.hasDuplicate(ctx, existing.ID, hash)
+		if err != nil {
+			return "", 0, err
+		}
+		if duplicate {
+			return SyncDuplicate, existing.ID, nil
+		}
+	}
+	_, reembedded, err := p.UpdateDocument(ctx, existing.ID, &model.UpdateDocumentRequest{
+		Title:    &doc.Title,
+		Content:  &doc.Content,
+		Metadata: doc.Metadata,
+	})
+	if errors.Is(err, database.ErrDuplicateDocument) {
+		return SyncDuplicate, existing.ID, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if !reembedded {
+		return SyncUnchanged, existing.ID, nil
+	}
+	return SyncUpdated, existing.ID, nil
+}
+
+// hasDuplicate memeriksa apakah dokumen aktif selain id sudah memiliki konten dengan hash.
+// Pemeriksaan ini menghindari embedding yang akan ditolak saat disimpan; penolakan
+// ErrDuplicateDocument tetap menangani permintaan lain yang menyimpan konten yang sama.
+func (p *Processor) hasDuplicate(ctx context.Context, id int, hash string) (bool, error) {
+	other, err := p.db.FindDocumentByContentHash(ctx, hash)
+	if errors.Is(err, database.ErrDocumentNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return other.ID != id, nil
+}
+
+// FindDocumentByMetadata mengambil dokumen yang metadata key-nya bernilai value
+func (p *Processor) FindDocumentByMetadata(ctx context.Context, key, value string) (*model.Document, error) {
+	return p.db.FindDocumentByMetadata(ctx, key, value)
+}
+
+// sameMetadata membandingkan metadata melalui representasi JSON-nya, karena angka dari database
+// dibaca sebagai float64 sedangkan metadata baru dapat berisi int
+func sameMetadata(a, b map[string]interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// DocumentIDsByMetadata mengembalikan ID dokumen untuk setiap nilai metadata key
+func (p *Processor) DocumentIDsByMetadata(ctx context.Context, key string) (map[string]int, error) {
+	return p.db.DocumentIDsByMetadata(ctx, key)
+}
@@ -0,0 +1,59 @@
+package rag
+
+import (
+	"context"
+	"rag-chat-bot/internal/chunking"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/tokenizer"
+	"testing"
+)
+
+func TestSyncDocument(t *testing.T) {
+	chunkers, err := chunking.NewSet(chunking.Options{Size: 100, Overlap: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newFakeStore()
+	embedder := &fakeEmbedder{}
+	p := NewProcessor(store, embedder, chunkers, tokenizer.Estimator{})
+	ctx := context.Background()
+
+	steps := []struct {
+		name         string
+		path         string
+		title        string
+		content      string
+		wantAction   string
+		wantEmbedded int
+	}{
+		{"create", "a.txt", "A", "Isi berkas A.", SyncCreated, 1},
+		{"same content", "a.txt", "A", "Isi   berkas A.", SyncUnchanged, 0},
+		{"new title", "a.txt", "A baru", "Isi berkas A.", SyncUnchanged, 0},
+		{"new content", "a.txt", "A baru", "Isi berkas A yang berubah.", SyncUpdated, 1},
+		{"duplicate new file", "b.txt", "B", "Isi berkas A yang berubah.", SyncDuplicate, 0},
+		{"create other", "c.txt", "C", "Isi berkas C.", SyncCreated, 1},
+		{"duplicate update", "c.txt", "C", "Isi berkas A yang berubah.", SyncDuplicate, 0},
+	}
+
+	for _, step := range steps {
+		embedder.embedded = 0
+		action, _, err := p.SyncDocument(ctx, "source_path", step.path, &model.Document{Title: step.title, Content: step.content})
+		if err != nil {
+			t.Fatalf("%s: SyncDocument() error = %v", step.name, err)
+		}
+		if action != step.wantAction {
+			t.Errorf("%s: action = %s, want %s", step.name, action, step.wantAction)
+		}
+		if embedder.embedded != step.wantEmbedded {
+			t.Errorf("%s: embedded %d chunks, want %d", step.name, embedder.embedded, step.wantEmbedded)
+		}
+	}
+
+	doc, err := p.FindDocumentByMetadata(ctx, "source_path", "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "A baru" {
+		t.Errorf("title = %q, want %q", doc.Title, "A baru")
+	}
+}
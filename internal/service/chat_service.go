@@ -6,7 +6,11 @@ import (
 	"log"
 	"rag-chat-bot/internal/database"
 	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/prompt"
 	"rag-chat-bot/internal/rag"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // ChatService mengelola layanan percakapan
@@ -25,10 +29,200 @@ func NewChatService(db *database.PostgresDB, retriever *rag.Retriever) *ChatServ
 
 // ProcessUserMessage memproses pesan pengguna dan menghasilkan respons
 func (s *ChatService) ProcessUserMessage(ctx context.Context, req *model.ChatRequest) (*model.ChatResponse, error) {
+	conversationID, chatMessages, err := s.startTurn(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate respons menggunakan RAG retriever
+	answer, err := s.retriever.GenerateResponseFromContext(ctx, req.Message, chatMessages, searchOptions(req))
+	if err != nil {
+		log.Printf("Error generating response: %v, will return generic response", err)
+		answer = &rag.Answer{Content: s.retriever.ErrorMessage(req.Message, searchOptions(req)), Sources: []model.Source{}}
+	}
+
+	// Simpan respons asisten
+	s.saveAssistantMessage(ctx, conversationID, answer.Content)
+
+	// Kembalikan respons
+	return &model.ChatResponse{
+		Success:      true,
+		Message:      answer.Content,
+		SessionID:    req.SessionID,
+		Sources:      answer.Sources,
+		AnswerSource: answer.AnswerSource,
+		Usage:        answer.Usage,
+	}, nil
+}
+
+// StreamUserMessage memproses pesan pengguna dan mengirim respons secara bertahap melalui send.
+// Pesan asisten yang sudah terkumpul tetap disimpan saat streaming selesai, gagal, atau dibatalkan.
+func (s *ChatService) StreamUserMessage(ctx context.Context, req *model.ChatRequest, send func(event string, data interface{}) error) error {
+	conversationID, chatMessages, err := s.startTurn(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	onSources := func(sources []model.Source) error {
+		return send(model.StreamEventSources, sources)
+	}
+	onToken := func(token string) error {
+		return send(model.StreamEventToken, map[string]string{"content": token})
+	}
+
+	answer, streamErr := s.retriever.StreamResponseFromContext(ctx, req.Message, chatMessages, searchOptions(req), onSources, onToken)
+
+	// Simpan jawaban yang sudah terkirim meskipun klien memutus koneksi
+	if answer != nil && answer.Content != "" {
+		s.saveAssistantMessage(context.WithoutCancel(ctx), conversationID, answer.Content)
+	}
+
+	if streamErr != nil {
+		log.Printf("Error streaming response: %v", streamErr)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return send(model.StreamEventError, map[string]string{"error": s.retriever.ErrorMessage(req.Message, searchOptions(req))})
+	}
+
+	// Event done membawa sumber yang sudah ditandai sesuai sitasi di jawaban lengkap
+	return send(model.StreamEventDone, &model.ChatResponse{
+		Success:      true,
+		Message:      answer.Content,
+		SessionID:    req.SessionID,
+		Sources:      answer.Sources,
+		AnswerSource: answer.AnswerSource,
+		Usage:        answer.Usage,
+	})
+}
+
+// maxSnippetRunes adalah panjang maksimum cuplikan hasil pencarian
+const maxSnippetRunes = 240
+
+// SearchDocuments menjalankan retrieval tanpa memanggil model chat dan mengembalikan potongan
+// dokumen beserta skor dan cuplikan yang menyorot kata kueri
+func (s *ChatService) SearchDocuments(ctx context.Context, req *model.SearchRequest) (*model.SearchResponse, error) {
+	docs, err := s.retriever.RetrieveRelevantDocuments(ctx, req.Query, rag.SearchOptions{
+		Filter:   req.Filter,
+		TopK:     req.TopK,
+		Mode:     req.Mode,
+		MinScore: req.MinScore,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.SearchResult, 0, len(docs))
+	for _, doc := range docs {
+		result := model.SearchResult{
+			DocumentID:   doc.ID,
+			Title:        doc.Title,
+			Score:        doc.Score,
+			VectorScore:  doc.VectorScore,
+			KeywordScore: doc.KeywordScore,
+			Section:      doc.Section(),
+			URL:          doc.SourceURL(),
+			Snippet:      rag.Highlight(doc.Text(), req.Query, maxSnippetRunes),
+			Content:      doc.Text(),
+			Metadata:     doc.Metadata,
+		}
+		if doc.Chunk != nil {
+			result.ChunkID = doc.Chunk.ID
+			result.ChunkIndex = doc.Chunk.ChunkIndex
+		}
+		results = append(results, result)
+	}
+
+	return &model.SearchResponse{
+		Success: true,
+		Query:   req.Query,
+		Mode:    s.retriever.Mode(req.Mode),
+		Results: results,
+	}, nil
+}
+
+// SelectPrompt memvalidasi pilihan template prompt permintaan chat. Jika req.Language kosong,
+// bahasa di acceptLanguage (nilai header Accept-Language) dengan bobot q tertinggi yang memiliki
+// template dipakai.
+func (s *ChatService) SelectPrompt(req *model.ChatRequest, acceptLanguage string) error {
+	prompts := s.retriever.Prompts()
+
+	if req.Language != "" {
+		if !prompts.HasLanguage(req.Language) {
+			return fmt.Errorf("unsupported language: %s", req.Language)
+		}
+		req.Language = prompt.NormalizeLanguage(req.Language)
+	} else {
+		for _, tag := range acceptedLanguages(acceptLanguage) {
+			if prompts.HasLanguage(tag) {
+				req.Language = prompt.NormalizeLanguage(tag)
+				break
+			}
+		}
+	}
+
+	if req.Collection != "" && !prompts.HasCollection(req.Collection) {
+		return fmt.Errorf("unknown collection: %s", req.Collection)
+	}
+	return nil
+}
+
+// acceptedLanguages mengurai header Accept-Language menjadi tag bahasa yang diurutkan menurut
+// bobot q, dari yang tertinggi. Tag dengan q=0 (ditolak) dan wildcard "*" dibuang.
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var languages []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = v
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		languages = append(languages, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+	return tags
+}
+
+// searchOptions membuat opsi pencarian dokumen dari permintaan chat
+func searchOptions(req *model.ChatRequest) rag.SearchOptions {
+	return rag.SearchOptions{
+		Filter:     req.Filter,
+		Language:   req.Language,
+		Collection: req.Collection,
+		Session:    req.SessionID,
+	}
+}
+
+// startTurn menyimpan pesan pengguna dan mengembalikan ID percakapan beserta riwayatnya
+func (s *ChatService) startTurn(ctx context.Context, req *model.ChatRequest) (int, []model.ChatMessage, error) {
 	// Dapatkan atau buat percakapan baru berdasarkan session ID
 	conversationID, err := s.db.GetConversationBySessionID(ctx, req.SessionID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting conversation: %w", err)
+		return 0, nil, fmt.Errorf("error getting conversation: %w", err)
 	}
 
 	// Simpan pesan pengguna
@@ -39,13 +233,13 @@ func (s *ChatService) ProcessUserMessage(ctx context.Context, req *model.ChatReq
 	}
 
 	if err := s.db.SaveMessage(ctx, userMsg); err != nil {
-		return nil, fmt.Errorf("error saving user message: %w", err)
+		return 0, nil, fmt.Errorf("error saving user message: %w", err)
 	}
 
 	// Ambil riwayat percakapan
 	messages, err := s.db.GetConversationMessages(ctx, conversationID)
 	if err != nil {
-		return nil, fmt.Errorf("error getting conversation history: %w", err)
+		return 0, nil, fmt.Errorf("error getting conversation history: %w", err)
 	}
 
 	// Konversi pesan ke format yang diperlukan oleh retriever
@@ -57,14 +251,11 @@ func (s *ChatService) ProcessUserMessage(ctx context.Context, req *model.ChatReq
 		})
 	}
 
-	// Generate respons menggunakan RAG retriever
-	response, err := s.retriever.GenerateResponseFromContext(ctx, req.Message, chatMessages)
-	if err != nil {
-		log.Printf("Error generating response: %v, will return generic response", err)
-		response = "Maaf, saya mengalami kesulitan dalam memproses permintaan Anda. Silakan coba lagi atau tanyakan dengan cara yang berbeda."
-	}
+	return conversationID, chatMessages, nil
+}
 
-	// Simpan respons asisten
+// saveAssistantMessage menyimpan respons asisten ke percakapan
+func (s *ChatService) saveAssistantMessage(ctx context.Context, conversationID int, response string) {
 	assistantMsg := &model.Message{
 		ConversationID: conversationID,
 		Role:           "assistant",
@@ -75,13 +266,6 @@ func (s *ChatService) ProcessUserMessage(ctx context.Context, req *model.ChatReq
 		log.Printf("Error saving assistant message: %v", err)
 		// Lanjutkan meskipun ada kesalahan penyimpanan
 	}
-
-	// Kembalikan respons
-	return &model.ChatResponse{
-		Success:   true,
-		Message:   response,
-		SessionID: req.SessionID,
-	}, nil
 }
 
 // GetConversationHistory mengambil riwayat percakapan
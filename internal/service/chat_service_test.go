@@ -0,0 +1,78 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"rag-chat-bot/internal/model"
+	"rag-chat-bot/internal/prompt"
+	"rag-chat-bot/internal/rag"
+	"reflect"
+	"testing"
+)
+
+func TestAcceptedLanguages(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"en-US", []string{"en-US"}},
+		{"fr, en;q=0.8, id;q=0.9", []string{"fr", "id", "en"}},
+		{"en;q=0.5, id;q=0.5", []string{"en", "id"}},
+		{"en;q=0, id", []string{"id"}},
+		{"*, en;q=0.1", []string{"en"}},
+		{" id ; q=0.7 ;level=1 , en;q=abc", []string{"en", "id"}},
+	}
+
+	for _, tt := range tests {
+		if got := acceptedLanguages(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("acceptedLanguages(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestSelectPrompt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "collections", "billing", "id", "system.tmpl")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("Sistem billing"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prompts, err := prompt.Load(dir, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat := NewChatService(nil, rag.NewRetriever(nil, nil, nil, rag.RetrieverOptions{Prompts: prompts}))
+
+	tests := []struct {
+		name           string
+		req            model.ChatRequest
+		acceptLanguage string
+		wantLanguage   string
+		wantErr        bool
+	}{
+		{"request language wins", model.ChatRequest{Language: "EN-gb"}, "id", "en", false},
+		{"unsupported request language", model.ChatRequest{Language: "fr"}, "en", "", true},
+		{"first supported header language", model.ChatRequest{}, "fr-FR, en-US;q=0.9, id;q=0.8", "en", false},
+		{"header weights", model.ChatRequest{}, "en;q=0.2, id", "id", false},
+		{"refused header language", model.ChatRequest{}, "en;q=0", "", false},
+		{"no supported header language", model.ChatRequest{}, "fr, de", "", false},
+		{"known collection", model.ChatRequest{Collection: "billing"}, "", "", false},
+		{"unknown collection", model.ChatRequest{Collection: "sales"}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			err := chat.SelectPrompt(&req, tt.acceptLanguage)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectPrompt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && req.Language != tt.wantLanguage {
+				t.Errorf("language = %q, want %q", req.Language, tt.wantLanguage)
+			}
+		})
+	}
+}
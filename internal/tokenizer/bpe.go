@@ -0,0 +1,196 @@
+package tokenizer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// cl100kSpecialTokens adalah token khusus encoding cl100k_base
+var cl100kSpecialTokens = map[string]int{
+	"<|endoftext|>":   100257,
+	"<|fim_prefix|>":  100258,
+	"<|fim_middle|>":  100259,
+	"<|fim_suffix|>":  100260,
+	"<|endofprompt|>": 100276,
+}
+
+// maxCacheEntries membatasi ukuran cache hasil encode per potongan teks
+const maxCacheEntries = 20000
+
+// BPE adalah tokenizer byte-pair encoding dengan format vocab tiktoken
+type BPE struct {
+	encoder map[string]int
+	decoder map[int][]byte
+	special map[string]int
+
+	mu    sync.RWMutex
+	cache map[string][]int
+}
+
+// NewCL100K membuat tokenizer cl100k_base dari isi file vocab tiktoken
+func NewCL100K(vocab []byte) (*BPE, error) {
+	encoder, err := parseTiktoken(vocab)
+	if err != nil {
+		return nil, err
+	}
+
+	bpe := &BPE{
+		encoder: encoder,
+		decoder: make(map[int][]byte, len(encoder)+len(cl100kSpecialTokens)),
+		special: cl100kSpecialTokens,
+		cache:   make(map[string][]int),
+	}
+	for token, rank := range encoder {
+		bpe.decoder[rank] = []byte(token)
+	}
+	for token, rank := range cl100kSpecialTokens {
+		bpe.decoder[rank] = []byte(token)
+	}
+
+	return bpe, nil
+}
+
+// parseTiktoken membaca format vocab tiktoken: satu token base64 dan peringkatnya per baris
+func parseTiktoken(vocab []byte) (map[string]int, error) {
+	encoder := make(map[string]int, 100256)
+
+	scanner := bufio.NewScanner(bytes.NewReader(vocab))
+	for line := 1; scanner.Scan(); line++ {
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid tokenizer vocab at line %d", line)
+		}
+
+		token, err := base64.StdEncoding.DecodeString(string(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid token at line %d: %w", line, err)
+		}
+		rank, err := strconv.Atoi(string(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rank at line %d: %w", line, err)
+		}
+		encoder[string(token)] = rank
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading tokenizer vocab: %w", err)
+	}
+	if len(encoder) == 0 {
+		return nil, fmt.Errorf("tokenizer vocab is empty")
+	}
+
+	return encoder, nil
+}
+
+// Count menghitung jumlah token sebuah teks
+func (b *BPE) Count(text string) int {
+	count := 0
+	for _, piece := range pretokenize(text) {
+		count += len(b.encodePiece(piece))
+	}
+	return count
+}
+
+// Encode mengubah teks menjadi daftar token. Token khusus diperlakukan sebagai teks biasa.
+func (b *BPE) Encode(text string) []int {
+	var tokens []int
+	for _, piece := range pretokenize(text) {
+		tokens = append(tokens, b.encodePiece(piece)...)
+	}
+	return tokens
+}
+
+// Decode mengubah daftar token kembali menjadi teks
+func (b *BPE) Decode(tokens []int) string {
+	var buf bytes.Buffer
+	for _, token := range tokens {
+		buf.Write(b.decoder[token])
+	}
+	return buf.String()
+}
+
+// encodePiece meng-encode satu potongan hasil pretokenisasi dengan cache
+func (b *BPE) encodePiece(piece string) []int {
+	if rank, ok := b.encoder[piece]; ok {
+		return []int{rank}
+	}
+
+	b.mu.RLock()
+	tokens, ok := b.cache[piece]
+	b.mu.RUnlock()
+	if ok {
+		return tokens
+	}
+
+	tokens = b.bytePairEncode([]byte(piece))
+
+	b.mu.Lock()
+	if len(b.cache) >= maxCacheEntries {
+		b.cache = make(map[string][]int)
+	}
+	b.cache[piece] = tokens
+	b.mu.Unlock()
+
+	return tokens
+}
+
+// bytePairEncode menggabungkan pasangan byte dengan peringkat terendah secara berulang
+// hingga tidak ada lagi pasangan yang terdapat di vocab
+func (b *BPE) bytePairEncode(piece []byte) []int {
+	// parts berisi batas-batas bagian; awalnya setiap byte adalah satu bagian
+	parts := make([]int, len(piece)+1)
+	for i := range parts {
+		parts[i] = i
+	}
+
+	rankOf := func(i int) int {
+		if i+2 >= len(parts) {
+			return math.MaxInt
+		}
+		if rank, ok := b.encoder[string(piece[parts[i]:parts[i+2]])]; ok {
+			return rank
+		}
+		return math.MaxInt
+	}
+
+	ranks := make([]int, len(parts))
+	for i := range ranks {
+		ranks[i] = rankOf(i)
+	}
+
+	for len(parts) > 2 {
+		minRank, minIdx := math.MaxInt, -1
+		for i := 0; i < len(ranks)-2; i++ {
+			if ranks[i] < minRank {
+				minRank, minIdx = ranks[i], i
+			}
+		}
+		if minIdx < 0 {
+			break
+		}
+
+		// Gabungkan bagian minIdx dan minIdx+1
+		parts = append(parts[:minIdx+1], parts[minIdx+2:]...)
+		ranks = append(ranks[:minIdx+1], ranks[minIdx+2:]...)
+		ranks[minIdx] = rankOf(minIdx)
+		if minIdx > 0 {
+			ranks[minIdx-1] = rankOf(minIdx - 1)
+		}
+	}
+
+	tokens := make([]int, 0, len(parts)-1)
+	for i := 0; i < len(parts)-1; i++ {
+		if rank, ok := b.encoder[string(piece[parts[i]:parts[i+1]])]; ok {
+			tokens = append(tokens, rank)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,163 @@
+package tokenizer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// testVocab membuat vocab tiktoken kecil: setiap byte dengan peringkat sama dengan nilainya,
+// ditambah token gabungan dengan peringkat berurutan mulai 256
+func testVocab(merges ...string) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < 256; i++ {
+		fmt.Fprintf(&buf, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(i)}), i)
+	}
+	for i, token := range merges {
+		fmt.Fprintf(&buf, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(token)), 256+i)
+	}
+	return buf.Bytes()
+}
+
+func TestBytePairEncodeMergeOrder(t *testing.T) {
+	// ab=256, bc=257, abc=258, cd=259, abab=260
+	bpe, err := NewCL100K(testVocab("ab", "bc", "abc", "cd", "abab"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want []int
+	}{
+		// ab (256) digabung lebih dulu daripada bc (257) dan cd (259), lalu abc (258)
+		{"abcd", []int{258, 'd'}},
+		{"bcab", []int{257, 256}},
+		// Peringkat sama digabung dari kiri
+		{"ababab", []int{260, 256}},
+		{"abab", []int{260}},
+		{"abcd bcab", []int{258, 'd', ' ', 257, 256}},
+		// Byte UTF-8 tanpa token gabungan tetap satu token per byte
+		{"é", []int{0xc3, 0xa9}},
+	}
+
+	for _, tt := range tests {
+		got := bpe.Encode(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Encode(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+		if count := bpe.Count(tt.text); count != len(tt.want) {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, count, len(tt.want))
+		}
+		if decoded := bpe.Decode(got); decoded != tt.text {
+			t.Errorf("Decode(Encode(%q)) = %q", tt.text, decoded)
+		}
+	}
+}
+
+func TestParseTiktokenInvalid(t *testing.T) {
+	for _, vocab := range []string{"YQ==\n", "!!! 1\n", "YQ== x\n"} {
+		if _, err := NewCL100K([]byte(vocab)); err == nil {
+			t.Errorf("NewCL100K(%q) succeeded, want error", vocab)
+		}
+	}
+}
+
+// loadCL100K memuat vocab cl100k_base asli yang di-bundle ke binary, dari TOKENIZER_VOCAB_FILE,
+// assets atau internal/tokenizer/vocab, atau melewati test jika vocab tidak ada
+func loadCL100K(t *testing.T) *BPE {
+	t.Helper()
+	vocab := embeddedVocab
+	for _, path := range []string{os.Getenv("TOKENIZER_VOCAB_FILE"), "../../assets/cl100k_base.tiktoken", "vocab/cl100k_base.tiktoken"} {
+		if len(vocab) > 0 || path == "" {
+			continue
+		}
+		vocab, _ = os.ReadFile(path)
+	}
+	if len(vocab) == 0 {
+		t.Skip("cl100k_base vocab not available, see README for the download step")
+	}
+	if sum := sha256.Sum256(vocab); hex.EncodeToString(sum[:]) != CL100KSHA256 {
+		t.Fatalf("vocab checksum %x does not match cl100k_base", sum)
+	}
+	bpe, err := NewCL100K(vocab)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bpe
+}
+
+func TestCL100KEncode(t *testing.T) {
+	bpe := loadCL100K(t)
+
+	// Token ID referensi dari tiktoken.get_encoding("cl100k_base").encode(text)
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{"hello world", []int{15339, 1917}},
+		{"Hello, world!", []int{9906, 11, 1917, 0}},
+		{"tiktoken is great!", []int{83, 1609, 5963, 374, 2294, 0}},
+		{"2 + 2 = 4", []int{17, 489, 220, 17, 284, 220, 19}},
+		{"!", []int{0}},
+		{" ", []int{220}},
+		{"\n", []int{198}},
+		{"お誕生日おめでとう", []int{33334, 45918, 243, 21990, 9080, 33334, 62004, 16556, 78699}},
+	}
+
+	for _, tt := range tests {
+		got := bpe.Encode(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Encode(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+		if count := bpe.Count(tt.text); count != len(tt.want) {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, count, len(tt.want))
+		}
+	}
+}
+
+func TestCL100KCount(t *testing.T) {
+	bpe := loadCL100K(t)
+
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"1234567", 3},
+		{"I'm", 2},
+		{"don't", 2},
+	}
+	for _, tt := range tests {
+		if got := bpe.Count(tt.text); got != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCL100KRoundTrip(t *testing.T) {
+	bpe := loadCL100K(t)
+
+	texts := []string{
+		"Hello, world!",
+		"I'm sure they'll say we've done it",
+		"a   b\n\n\tc  \r\n",
+		"你好，世界",
+		"こんにちは、世界",
+		"hi 👋🏽! 👨‍👩‍👧",
+		"func main() {\n\tfmt.Println(\"ok\")\n}\n",
+	}
+	for _, text := range texts {
+		tokens := bpe.Encode(text)
+		if got := bpe.Decode(tokens); got != text {
+			t.Errorf("Decode(Encode(%q)) = %q", text, got)
+		}
+		if count := bpe.Count(text); count != len(tokens) {
+			t.Errorf("Count(%q) = %d, Encode returned %d tokens", text, count, len(tokens))
+		}
+	}
+}
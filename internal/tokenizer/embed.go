@@ -0,0 +1,15 @@
+//go:build tokenizer_embed
+
+package tokenizer
+
+import "embed"
+
+// vocabFS berisi direktori vocab yang di-bundle ke binary. Unduh cl100k_base.tiktoken ke
+// internal/tokenizer/vocab lalu build dengan -tags tokenizer_embed; tanpa file tersebut binary
+// tetap dapat di-build dan Load memakai TOKENIZER_VOCAB_FILE.
+//
+//go:embed all:vocab
+var vocabFS embed.FS
+
+// embeddedVocab berisi vocab cl100k_base yang di-bundle ke binary, kosong jika file tidak ada
+var embeddedVocab, _ = vocabFS.ReadFile("vocab/cl100k_base.tiktoken")
@@ -0,0 +1,6 @@
+//go:build !tokenizer_embed
+
+package tokenizer
+
+// embeddedVocab kosong jika binary di-build tanpa tag tokenizer_embed
+var embeddedVocab []byte
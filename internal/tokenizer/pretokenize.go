@@ -0,0 +1,144 @@
+package tokenizer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// pretokenize memecah teks menjadi potongan sebelum BPE, setara dengan pola regex cl100k_base:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// Paket regexp Go tidak mendukung lookahead, sehingga pola diimplementasikan secara manual.
+func pretokenize(text string) []string {
+	var pieces []string
+	for i := 0; i < len(text); {
+		end := matchPiece(text, i)
+		pieces = append(pieces, text[i:end])
+		i = end
+	}
+	return pieces
+}
+
+// matchPiece mengembalikan posisi akhir potongan yang dimulai di i
+func matchPiece(text string, i int) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+
+	// (?i:'s|'t|'re|'ve|'m|'ll|'d)
+	if r == '\'' {
+		if end := matchContraction(text, i+size); end > 0 {
+			return end
+		}
+	}
+
+	// [^\r\n\p{L}\p{N}]?\p{L}+
+	if isLetter(r) {
+		return skipWhile(text, i+size, isLetter)
+	}
+	if r != '\r' && r != '\n' && !isNumber(r) && i+size < len(text) {
+		if next, _ := utf8.DecodeRuneInString(text[i+size:]); isLetter(next) {
+			return skipWhile(text, i+size, isLetter)
+		}
+	}
+
+	// \p{N}{1,3}
+	if isNumber(r) {
+		end := i + size
+		for n := 1; n < 3 && end < len(text); n++ {
+			next, nextSize := utf8.DecodeRuneInString(text[end:])
+			if !isNumber(next) {
+				break
+			}
+			end += nextSize
+		}
+		return end
+	}
+
+	// ?[^\s\p{L}\p{N}]+[\r\n]*
+	start := i
+	if r == ' ' && i+size < len(text) {
+		if next, _ := utf8.DecodeRuneInString(text[i+size:]); isPunct(next) {
+			start = i + size
+		}
+	}
+	if first, _ := utf8.DecodeRuneInString(text[start:]); isPunct(first) {
+		end := skipWhile(text, start, isPunct)
+		return skipWhile(text, end, func(r rune) bool { return r == '\r' || r == '\n' })
+	}
+
+	// Sisa pola hanya berlaku untuk spasi
+	wsEnd := skipWhile(text, i, unicode.IsSpace)
+
+	// \s*[\r\n]+
+	lastNewline := -1
+	for j := i; j < wsEnd; {
+		ws, wsSize := utf8.DecodeRuneInString(text[j:])
+		if ws == '\r' || ws == '\n' {
+			lastNewline = j + wsSize
+		}
+		j += wsSize
+	}
+	if lastNewline > 0 {
+		return lastNewline
+	}
+
+	// \s+(?!\S) lalu \s+
+	if wsEnd < len(text) {
+		_, lastSize := utf8.DecodeLastRuneInString(text[i:wsEnd])
+		if wsEnd-lastSize > i {
+			return wsEnd - lastSize
+		}
+	}
+	if wsEnd > i {
+		return wsEnd
+	}
+
+	return i + size
+}
+
+// matchContraction mencocokkan akhiran kontraksi bahasa Inggris setelah apostrof
+func matchContraction(text string, i int) int {
+	for _, suffix := range []string{"s", "t", "re", "ve", "m", "ll", "d"} {
+		if i+len(suffix) > len(text) {
+			continue
+		}
+		match := true
+		for k := 0; k < len(suffix); k++ {
+			if text[i+k]|0x20 != suffix[k] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i + len(suffix)
+		}
+	}
+	return 0
+}
+
+// skipWhile mengembalikan posisi rune pertama mulai dari i yang tidak memenuhi fn
+func skipWhile(text string, i int, fn func(rune) bool) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !fn(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// isLetter setara dengan \p{L}
+func isLetter(r rune) bool {
+	return unicode.IsLetter(r)
+}
+
+// isNumber setara dengan \p{N}
+func isNumber(r rune) bool {
+	return unicode.IsNumber(r)
+}
+
+// isPunct setara dengan [^\s\p{L}\p{N}]
+func isPunct(r rune) bool {
+	return !unicode.IsSpace(r) && !isLetter(r) && !isNumber(r)
+}
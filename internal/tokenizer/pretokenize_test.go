@@ -0,0 +1,45 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPretokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"ascii", "Hello world", []string{"Hello", " world"}},
+		{"punctuation", "Hello, world!", []string{"Hello", ",", " world", "!"}},
+		{"punctuation run with newline", "foo... bar!!\n", []string{"foo", "...", " bar", "!!\n"}},
+		{"space before punctuation", " (x)", []string{" (", "x", ")"}},
+		{"symbol prefix", "$hello", []string{"$hello"}},
+		{"contractions", "I'm don't WE'LL they're", []string{"I", "'m", " don", "'t", " WE", "'LL", " they", "'re"}},
+		{"contraction prefix", "'sup", []string{"'s", "up"}},
+		{"apostrophe prefix", "'hello", []string{"'hello"}},
+		{"digits in groups of three", "1234567", []string{"123", "456", "7"}},
+		{"letters then digits", "abc123", []string{"abc", "123"}},
+		{"space before digits", "x 2024", []string{"x", " ", "202", "4"}},
+		{"non-ascii digits", "٣٤٥٦", []string{"٣٤٥", "٦"}},
+		{"whitespace run", "a   b", []string{"a", "  ", " b"}},
+		{"tab prefix", "a\tb", []string{"a", "\tb"}},
+		{"newlines", "a\n\nb", []string{"a", "\n\n", "b"}},
+		{"spaces around newline", "a \n  b", []string{"a", " \n", " ", " b"}},
+		{"crlf", "a\r\n", []string{"a", "\r\n"}},
+		{"trailing spaces", "end   ", []string{"end", "   "}},
+		{"cjk", "你好世界", []string{"你好世界"}},
+		{"cjk punctuation", "こんにちは、世界", []string{"こんにちは", "、世界"}},
+		{"emoji", "hi 👋🏽!", []string{"hi", " 👋🏽!"}},
+		{"leading emoji", "👍 ok", []string{"👍", " ok"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pretokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pretokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
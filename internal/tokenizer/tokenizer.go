@@ -0,0 +1,102 @@
+package tokenizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Counter menghitung jumlah token sebuah teks
+type Counter interface {
+	Count(text string) int
+}
+
+// Tokenizer mengubah teks menjadi token dan sebaliknya
+type Tokenizer interface {
+	Counter
+	Encode(text string) []int
+	Decode(tokens []int) string
+}
+
+// Estimator memperkirakan jumlah token tanpa vocab, sekitar 4 karakter per token.
+// Digunakan saat file vocab tidak tersedia.
+type Estimator struct{}
+
+// Count memperkirakan jumlah token sebuah teks
+func (Estimator) Count(text string) int {
+	if text == "" {
+		return 0
+	}
+	return len(text)/4 + 1
+}
+
+// NoVocab adalah nilai path untuk Load yang secara eksplisit memakai Estimator tanpa file vocab
+const NoVocab = "none"
+
+// CL100KSHA256 adalah checksum SHA-256 file cl100k_base.tiktoken yang diterbitkan OpenAI
+const CL100KSHA256 = "223921b76ee99bde995b7ff738513eef100fb51d18c93597a113bcffe865b2a7"
+
+// Load memuat tokenizer cl100k. Vocab yang ikut di-bundle ke binary (build tag tokenizer_embed)
+// diutamakan, lalu file vocab di path. Estimator dipakai jika path bernilai NoVocab atau file
+// vocab tidak ada, sehingga server tetap dapat berjalan dengan jumlah token perkiraan.
+func Load(path string) (Counter, error) {
+	if len(embeddedVocab) > 0 {
+		return loadVocab(embeddedVocab)
+	}
+
+	if path == NoVocab {
+		log.Printf("Tokenizer vocab disabled, token counts are estimated at ~4 characters per token")
+		return Estimator{}, nil
+	}
+
+	vocab, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Printf("Tokenizer vocab %s not found, token counts are estimated at ~4 characters per token; download cl100k_base.tiktoken for exact counts", path)
+			return Estimator{}, nil
+		}
+		return nil, fmt.Errorf("error reading tokenizer vocab: %w", err)
+	}
+
+	return loadVocab(vocab)
+}
+
+// loadVocab membuat tokenizer dari vocab dan memperingatkan jika checksum-nya bukan checksum
+// cl100k_base, misalnya karena file terpotong atau vocab model lain
+func loadVocab(vocab []byte) (*BPE, error) {
+	if sum := sha256.Sum256(vocab); hex.EncodeToString(sum[:]) != CL100KSHA256 {
+		log.Printf("Tokenizer vocab checksum does not match cl100k_base, token counts may be wrong")
+	}
+	return NewCL100K(vocab)
+}
+
+// Truncate memotong text agar tidak lebih dari maxTokens token. Tokenizer memotong tepat di batas
+// token; Counter lain memakai pencarian biner pada jumlah karakter.
+func Truncate(counter Counter, text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	if counter.Count(text) <= maxTokens {
+		return text
+	}
+
+	if tok, ok := counter.(Tokenizer); ok {
+		// Token terakhir dapat berisi sebagian byte karakter UTF-8
+		return strings.ToValidUTF8(tok.Decode(tok.Encode(text)[:maxTokens]), "")
+	}
+
+	runes := []rune(text)
+	low, high := 0, len(runes)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if counter.Count(string(runes[:mid])) <= maxTokens {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return string(runes[:low])
+}
@@ -0,0 +1,54 @@
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	if len(embeddedVocab) > 0 {
+		t.Skip("binary bundles the cl100k_base vocab")
+	}
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.tiktoken")
+	if err := os.WriteFile(valid, testVocab("ab"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.tiktoken")
+	if err := os.WriteFile(invalid, []byte("bukan vocab\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantBPE bool
+		wantErr bool
+	}{
+		{"disabled", NoVocab, false, false},
+		{"missing file falls back to estimator", filepath.Join(dir, "missing.tiktoken"), false, false},
+		{"vocab file", valid, true, false},
+		{"invalid vocab", invalid, false, true},
+		{"unreadable path", dir, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, err := Load(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, isBPE := counter.(*BPE); isBPE != tt.wantBPE {
+				t.Errorf("Load() = %T, want BPE %v", counter, tt.wantBPE)
+			}
+			if _, isEstimator := counter.(Estimator); !tt.wantBPE && !isEstimator {
+				t.Errorf("Load() = %T, want Estimator", counter)
+			}
+		})
+	}
+}
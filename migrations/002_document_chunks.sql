@@ -0,0 +1,26 @@
+-- Tabel untuk menyimpan potongan dokumen beserta embedding-nya
+CREATE TABLE document_chunks (
+    id SERIAL PRIMARY KEY,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    chunk_index INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    start_offset INTEGER NOT NULL, -- Posisi byte awal potongan di konten dokumen
+    end_offset INTEGER NOT NULL,   -- Posisi byte akhir potongan di konten dokumen
+    metadata JSONB DEFAULT '{}'::jsonb,
+    embedding VECTOR(1536), -- Harus sama dengan dimensi model embedding
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (document_id, chunk_index)
+);
+
+-- Indeks untuk pencarian vektor dengan metode HNSW
+CREATE INDEX ON document_chunks USING hnsw (embedding vector_cosine_ops);
+CREATE INDEX idx_document_chunks_document_id ON document_chunks(document_id);
+
+-- Pindahkan embedding lama sebagai satu potongan utuh per dokumen
+INSERT INTO document_chunks (document_id, chunk_index, content, start_offset, end_offset, embedding, created_at)
+SELECT DISTINCT ON (d.id) d.id, 0, d.content, 0, octet_length(d.content), e.embedding, e.created_at
+FROM documents d
+JOIN document_embeddings e ON e.document_id = d.id
+ORDER BY d.id, e.created_at DESC;
+
+DROP TABLE document_embeddings;
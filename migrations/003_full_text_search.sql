@@ -0,0 +1,7 @@
+-- Kolom tsvector untuk pencarian kata kunci. Konfigurasi 'simple' tidak melakukan stemming
+-- sehingga cocok untuk teks campuran bahasa Indonesia/Inggris, identifier dan kode error.
+ALTER TABLE document_chunks
+    ADD COLUMN content_tsv TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+-- Indeks GIN untuk full-text search
+CREATE INDEX idx_document_chunks_content_tsv ON document_chunks USING GIN (content_tsv);
@@ -0,0 +1,2 @@
+-- Indeks GIN untuk filter metadata dokumen (operator containment @> dan ?)
+CREATE INDEX idx_documents_metadata ON documents USING GIN (metadata);
@@ -0,0 +1,6 @@
+-- Waktu terakhir dokumen diperbarui melalui API
+ALTER TABLE documents ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+UPDATE documents SET updated_at = created_at;
+
+-- Indeks untuk daftar dokumen yang diurutkan berdasarkan waktu pembuatan
+CREATE INDEX idx_documents_created_at ON documents(created_at);
@@ -0,0 +1,29 @@
+-- Hash SHA-256 dari konten yang spasinya dinormalisasi (sama dengan rag.ContentHash) untuk
+-- mendeteksi dokumen dan potongan duplikat. Dokumen yang digantikan versi baru diberi
+-- superseded_at dan tidak lagi muncul di pencarian.
+ALTER TABLE documents
+    ADD COLUMN content_hash TEXT,
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN previous_version_id INTEGER REFERENCES documents(id) ON DELETE SET NULL,
+    ADD COLUMN superseded_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE document_chunks ADD COLUMN content_hash TEXT;
+
+UPDATE document_chunks
+SET content_hash = encode(sha256(convert_to(btrim(regexp_replace(content, '\s+', ' ', 'g')), 'UTF8')), 'hex');
+
+-- Duplikat yang sudah ada tidak dihapus; hanya dokumen tertua untuk setiap konten yang diberi hash
+UPDATE documents d
+SET content_hash = h.content_hash
+FROM (
+    SELECT DISTINCT ON (content_hash) id, content_hash
+    FROM (
+        SELECT id, encode(sha256(convert_to(btrim(regexp_replace(content, '\s+', ' ', 'g')), 'UTF8')), 'hex') AS content_hash
+        FROM documents
+    ) hashed
+    ORDER BY content_hash, id
+) h
+WHERE d.id = h.id;
+
+-- Hanya boleh ada satu versi aktif untuk setiap konten
+CREATE UNIQUE INDEX idx_documents_content_hash ON documents(content_hash) WHERE superseded_at IS NULL;
+CREATE INDEX idx_document_chunks_content_hash ON document_chunks(content_hash);